@@ -50,6 +50,15 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 	return element.Value.(*entry[K, V]).value, true
 }
 
+// Contains 判断键是否存在，不会改变键的使用顺序。
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.items[key]
+	return ok
+}
+
 // Put 写入键值，并在容量超限时淘汰最近最少使用项。
 func (c *Cache[K, V]) Put(key K, value V) {
 	c.mu.Lock()
@@ -62,6 +62,28 @@ func TestCacheUpdateDoesNotGrowLength(t *testing.T) {
 	}
 }
 
+func TestCacheContainsDoesNotPromote(t *testing.T) {
+	cache, err := NewCache[string, int](2)
+	if err != nil {
+		t.Fatalf("NewCache returned error: %v", err)
+	}
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if !cache.Contains("a") {
+		t.Fatal("expected key a to exist")
+	}
+
+	cache.Put("c", 3)
+
+	if cache.Contains("a") {
+		t.Fatal("expected key a to be evicted")
+	}
+	if cache.Contains("missing") {
+		t.Fatal("expected missing key to not exist")
+	}
+}
+
 func TestCacheDelete(t *testing.T) {
 	cache, err := NewCache[string, int](2)
 	if err != nil {
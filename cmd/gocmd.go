@@ -41,6 +41,41 @@ func GoRun(target string, buildFlags []string) (string, error) {
 	return string(output), nil
 }
 
+// GoBuild 执行go build命令
+//
+// 构建指定目标的可执行文件，支持传入构建标志。构建出的程序用于在当前机器上运行，
+// 所以会忽略环境变量中的GOOS和GOARCH，交叉编译时也能构建出可以执行的程序。
+//
+// Params:
+//
+//   - target: 要构建的目标文件或package路径。
+//   - output: 可执行文件的输出路径。
+//   - buildFlags: 构建标志列表。
+//
+// Returns:
+//
+//	error: 如果构建过程中发生错误，返回相应的错误信息。
+//
+// Example:
+//
+//	err := GoBuild("main.go", "bin/main", []string{"-trimpath"})
+//	if err != nil {
+//	    log.Printf("Build failed: %v", err)
+//	}
+func GoBuild(target, output string, buildFlags []string) error {
+	args := []string{"build", "-o", output}
+	args = append(args, buildFlags...)
+	args = append(args, target)
+
+	cmd := New(append([]string{"go"}, args...)...)
+	// 空的GOOS和GOARCH会使用go命令所在机器的系统和架构。
+	cmd.SetEnv(append(os.Environ(), "GOOS=", "GOARCH="))
+	if _, err := cmd.Run(); err != nil {
+		return fmt.Errorf("taurus_go/cmd build error:\n%s", err)
+	}
+	return nil
+}
+
 // GoList 执行go list命令
 //
 // 执行指定目标的go list命令，支持传入构建标志。
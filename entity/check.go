@@ -0,0 +1,18 @@
+package entity
+
+import "encoding/json"
+
+// CheckBuilder 实体表的CHECK约束构建器，在实体的Checks()方法中返回，
+// 通常使用entity/check包中的check.New创建。
+type CheckBuilder interface {
+	// Descriptor codegen中使用，用于获取约束的描述。
+	Descriptor() *CheckDescriptor
+}
+
+// CheckDescriptor CHECK约束的描述。
+type CheckDescriptor struct {
+	// Name 约束的名称，不能为空。
+	Name string
+	// Expr 序列化后的check.Cond，codegen通过它生成SQL和保存之前的检查。
+	Expr json.RawMessage
+}
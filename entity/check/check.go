@@ -0,0 +1,419 @@
+// Package check 提供CHECK约束的表达式，用于字段的CheckExpr和实体的Checks()。
+// 表达式可以生成不同数据库的SQL，也可以在Go中计算，生成的代码在保存之前检查实体是否满足约束。
+//
+// 例如：
+//
+//	e.Age.CheckExpr(func(age check.Expr) check.Cond {
+//		return age.GTE(0).And(age.LT(150))
+//	})
+//	check.New("chk_user_name", check.Length(check.Field(e.Name)).GTE(3))
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// 表达式节点的类型。
+const (
+	opColumn  = "column"
+	opValue   = "value"
+	opLength  = "length"
+	opEQ      = "="
+	opNEQ     = "<>"
+	opGT      = ">"
+	opGTE     = ">="
+	opLT      = "<"
+	opLTE     = "<="
+	opIn      = "in"
+	opLike    = "like"
+	opIsNull  = "is_null"
+	opNotNull = "not_null"
+	opAnd     = "and"
+	opOr      = "or"
+	opNot     = "not"
+)
+
+// node 表达式树中的节点，序列化后写入生成的代码。
+type node struct {
+	// Op 节点的类型。
+	Op string `json:"op"`
+	// Name 列名，Op为column时使用。
+	Name string `json:"name,omitempty"`
+	// Value 字面量，Op为value时使用。
+	Value json.RawMessage `json:"value,omitempty"`
+	// Args 子节点。
+	Args []*node `json:"args,omitempty"`
+}
+
+type (
+	// Expr 表达式中的值，可以是列、字面量或者函数。
+	Expr struct {
+		n *node
+	}
+	// Cond 结果为布尔值的条件，可以作为CHECK约束。
+	Cond struct {
+		n *node
+	}
+	// Row 在Go中计算条件时每一列的值，键为列名，值为nil或者nil指针时表示NULL。
+	Row map[string]any
+)
+
+// Col 通过列名引用一列。
+//
+// Params:
+//
+//   - name: 数据库中的列名。
+func Col(name string) Expr {
+	return Expr{&node{Op: opColumn, Name: name}}
+}
+
+// Field 引用实体的字段，字段需要已经初始化，例如在Fields()和Checks()中使用。
+//
+// Params:
+//
+//   - f: 实体的字段。
+func Field(f entity.FieldBuilder) Expr {
+	if f == nil || f.Descriptor() == nil {
+		panic("taurus_go/entity check: field is not initialized.")
+	}
+	return Col(f.Descriptor().AttrName)
+}
+
+// Val 字面量，支持字符串、布尔值和数字。
+//
+// Params:
+//
+//   - v: 字面量的值。
+func Val(v any) Expr {
+	v, err := normalize(v)
+	if err != nil {
+		panic(fmt.Sprintf("taurus_go/entity check: %v", err))
+	}
+	if v == nil {
+		panic("taurus_go/entity check: value can not be nil, use IsNull.")
+	}
+	if _, ok := v.(time.Time); ok {
+		panic("taurus_go/entity check: time value is not supported.")
+	}
+	b, _ := json.Marshal(v)
+	return Expr{&node{Op: opValue, Value: b}}
+}
+
+// Length 字符串的长度，按照字符计算。
+//
+// Params:
+//
+//   - e: 字符串的值。
+func Length(e Expr) Expr {
+	return Expr{&node{Op: opLength, Args: []*node{e.n}}}
+}
+
+// operand 把Expr或者字面量转换为节点。
+func operand(v any) *node {
+	if e, ok := v.(Expr); ok {
+		return e.n
+	}
+	return Val(v).n
+}
+
+// compare 创建比较两个值的条件。
+func (e Expr) compare(op string, v any) Cond {
+	return Cond{&node{Op: op, Args: []*node{e.n, operand(v)}}}
+}
+
+// EQ 等于，v可以是Expr或者字面量。
+func (e Expr) EQ(v any) Cond { return e.compare(opEQ, v) }
+
+// NEQ 不等于，v可以是Expr或者字面量。
+func (e Expr) NEQ(v any) Cond { return e.compare(opNEQ, v) }
+
+// GT 大于，v可以是Expr或者字面量。
+func (e Expr) GT(v any) Cond { return e.compare(opGT, v) }
+
+// GTE 大于等于，v可以是Expr或者字面量。
+func (e Expr) GTE(v any) Cond { return e.compare(opGTE, v) }
+
+// LT 小于，v可以是Expr或者字面量。
+func (e Expr) LT(v any) Cond { return e.compare(opLT, v) }
+
+// LTE 小于等于，v可以是Expr或者字面量。
+func (e Expr) LTE(v any) Cond { return e.compare(opLTE, v) }
+
+// Between 在lo和hi之间，包括lo和hi。
+func (e Expr) Between(lo, hi any) Cond {
+	return And(e.GTE(lo), e.LTE(hi))
+}
+
+// In 等于其中的一个值，vs可以是Expr或者字面量。
+func (e Expr) In(vs ...any) Cond {
+	if len(vs) == 0 {
+		panic("taurus_go/entity check: IN requires at least one value.")
+	}
+	args := []*node{e.n}
+	for _, v := range vs {
+		args = append(args, operand(v))
+	}
+	return Cond{&node{Op: opIn, Args: args}}
+}
+
+// NotIn 不等于其中的任何一个值。
+func (e Expr) NotIn(vs ...any) Cond {
+	return Not(e.In(vs...))
+}
+
+// Like 匹配LIKE的模式，%匹配任意个字符，_匹配一个字符，\转义。
+//
+// Params:
+//
+//   - pattern: LIKE的模式。
+func (e Expr) Like(pattern string) Cond {
+	return Cond{&node{Op: opLike, Args: []*node{e.n, Val(pattern).n}}}
+}
+
+// IsNull 值为NULL。
+func (e Expr) IsNull() Cond {
+	return Cond{&node{Op: opIsNull, Args: []*node{e.n}}}
+}
+
+// NotNull 值不为NULL。
+func (e Expr) NotNull() Cond {
+	return Cond{&node{Op: opNotNull, Args: []*node{e.n}}}
+}
+
+// And 所有的条件都成立。
+func And(cs ...Cond) Cond {
+	return logic(opAnd, cs)
+}
+
+// Or 任意一个条件成立。
+func Or(cs ...Cond) Cond {
+	return logic(opOr, cs)
+}
+
+// Not 条件不成立。
+func Not(c Cond) Cond {
+	return Cond{&node{Op: opNot, Args: []*node{c.n}}}
+}
+
+// logic 创建AND或者OR，只有一个条件时直接返回这个条件。
+func logic(op string, cs []Cond) Cond {
+	if len(cs) == 0 {
+		panic(fmt.Sprintf("taurus_go/entity check: %s requires at least one condition.", op))
+	}
+	if len(cs) == 1 {
+		return cs[0]
+	}
+	args := make([]*node, len(cs))
+	for i, c := range cs {
+		args[i] = c.n
+	}
+	return Cond{&node{Op: op, Args: args}}
+}
+
+// And 这个条件和cs都成立。
+func (c Cond) And(cs ...Cond) Cond {
+	return And(append([]Cond{c}, cs...)...)
+}
+
+// Or 这个条件或者cs中任意一个成立。
+func (c Cond) Or(cs ...Cond) Cond {
+	return Or(append([]Cond{c}, cs...)...)
+}
+
+// Columns 返回条件中引用的列，按照名称排序。
+func (c Cond) Columns() []string {
+	columns := []string{}
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.Op == opColumn && !slices.Contains(columns, n.Name) {
+			columns = append(columns, n.Name)
+		}
+		for _, a := range n.Args {
+			walk(a)
+		}
+	}
+	if c.n != nil {
+		walk(c.n)
+	}
+	slices.Sort(columns)
+	return columns
+}
+
+// MarshalJSON 实现了[json.Marshaler]，不会转义比较运算符中的<和>。
+func (c Cond) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c.n); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalJSON 实现了[json.Unmarshaler]，会检查节点是否完整。
+func (c *Cond) UnmarshalJSON(b []byte) error {
+	n := &node{}
+	if err := json.Unmarshal(b, n); err != nil {
+		return err
+	}
+	if err := n.validate(true); err != nil {
+		return err
+	}
+	c.n = n
+	return nil
+}
+
+// Parse 解析序列化后的条件。
+//
+// Params:
+//
+//   - data: 通过json.Marshal序列化的条件。
+func Parse(data []byte) (Cond, error) {
+	var c Cond
+	err := json.Unmarshal(data, &c)
+	return c, err
+}
+
+// validate 检查节点的类型和子节点的数量。
+//
+// Params:
+//
+//   - cond: 节点是否应该是条件。
+func (n *node) validate(cond bool) error {
+	if n == nil {
+		return fmt.Errorf("check: missing expression")
+	}
+	var args []bool
+	switch n.Op {
+	case opColumn:
+		if n.Name == "" {
+			return fmt.Errorf("check: column name is empty")
+		}
+	case opValue:
+		if len(n.Value) == 0 {
+			return fmt.Errorf("check: value is empty")
+		}
+	case opLength:
+		args = []bool{false}
+	case opEQ, opNEQ, opGT, opGTE, opLT, opLTE, opLike:
+		args = []bool{false, false}
+	case opIsNull, opNotNull:
+		args = []bool{false}
+	case opIn:
+		if len(n.Args) < 2 {
+			return fmt.Errorf("check: IN requires at least one value")
+		}
+		args = make([]bool, len(n.Args))
+	case opAnd, opOr:
+		if len(n.Args) < 2 {
+			return fmt.Errorf("check: %s requires at least two conditions", n.Op)
+		}
+		args = make([]bool, len(n.Args))
+		for i := range args {
+			args[i] = true
+		}
+	case opNot:
+		args = []bool{true}
+	default:
+		return fmt.Errorf("check: unknown operator %q", n.Op)
+	}
+	isCond := n.Op != opColumn && n.Op != opValue && n.Op != opLength
+	if isCond != cond {
+		return fmt.Errorf("check: operator %q can not be used here", n.Op)
+	}
+	if len(n.Args) != len(args) {
+		return fmt.Errorf("check: operator %q requires %d arguments, got %d", n.Op, len(args), len(n.Args))
+	}
+	for i, a := range n.Args {
+		if err := a.validate(args[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value 返回字面量节点的值，数字为json.Number。
+func (n *node) value() (any, error) {
+	d := json.NewDecoder(bytes.NewReader(n.Value))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Constraint 有名称的CHECK约束，实现了[entity.CheckBuilder]，在实体的Checks()方法中返回。
+type Constraint struct {
+	// Name 约束的名称。
+	Name string
+	// Cond 约束的条件。
+	Cond Cond
+}
+
+// New 创建一个有名称的CHECK约束。
+//
+// Params:
+//
+//   - name: 约束的名称。
+//   - c: 约束的条件。
+func New(name string, c Cond) *Constraint {
+	return &Constraint{Name: name, Cond: c}
+}
+
+// MustParse 解析序列化后的约束，生成的代码中使用，解析失败时panic。
+//
+// Params:
+//
+//   - name: 约束的名称。
+//   - data: 序列化后的条件。
+func MustParse(name string, data string) *Constraint {
+	c, err := Parse([]byte(data))
+	if err != nil {
+		panic(fmt.Sprintf("taurus_go/entity check %q: %v", name, err))
+	}
+	return New(name, c)
+}
+
+// Descriptor 实现了[entity.CheckBuilder]，codegen中使用。
+func (c *Constraint) Descriptor() *entity.CheckDescriptor {
+	b, _ := c.Cond.MarshalJSON()
+	return &entity.CheckDescriptor{Name: c.Name, Expr: b}
+}
+
+// Validate 在Go中检查行是否满足约束，和数据库一样，条件的结果为NULL时也满足约束。
+//
+// Params:
+//
+//   - table: 实体表的名称，用于错误信息。
+//   - row: 每一列的值。
+//
+// ErrCodes:
+//
+//   - Err_0100030014
+func (c *Constraint) Validate(table string, row Row) error {
+	ok, err := c.Cond.Eval(row)
+	if err != nil {
+		return fmt.Errorf("check constraint %s: %w", c.Name, err)
+	}
+	if !ok {
+		return entity.Err_0100030014.Sprintf(table, c.Name)
+	}
+	return nil
+}
+
+// SQL 生成数据库中的条件。
+//
+// Params:
+//
+//   - d: 数据库类型。
+func (c Cond) SQL(d dialect.DbDriver) string {
+	return render(c.n, d)
+}
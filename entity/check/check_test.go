@@ -0,0 +1,115 @@
+package check
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+func TestCondSQL(t *testing.T) {
+	age, name := Col("age"), Col("name")
+	cases := []struct {
+		cond  Cond
+		pg    string
+		mysql string
+	}{
+		{age.Between(0, 150), `(("age" >= 0) AND ("age" <= 150))`, "((`age` >= 0) AND (`age` <= 150))"},
+		{Length(name).GTE(3), `(length("name") >= 3)`, "(CHAR_LENGTH(`name`) >= 3)"},
+		{name.In("a", "it's"), `("name" IN ('a', 'it''s'))`, "(`name` IN ('a', 'it''s'))"},
+		{Not(name.Like("%@%")).Or(name.IsNull()), `((NOT ("name" LIKE '%@%')) OR ("name" IS NULL))`, "((NOT (`name` LIKE '%@%')) OR (`name` IS NULL))"},
+		{Col("min").LT(Col("max")), `("min" < "max")`, "(`min` < `max`)"},
+		{Col("active").EQ(true), `("active" = TRUE)`, "(`active` = TRUE)"},
+	}
+	for _, c := range cases {
+		if got := c.cond.SQL(dialect.PostgreSQL); got != c.pg {
+			t.Fatalf("PostgreSQL的语句不正确: %s", got)
+		}
+		if got := c.cond.SQL(dialect.MySQL); got != c.mysql {
+			t.Fatalf("MySQL的语句不正确: %s", got)
+		}
+	}
+}
+
+func TestCondEval(t *testing.T) {
+	name := "bob"
+	var none *string
+	cases := []struct {
+		cond Cond
+		row  Row
+		want bool
+	}{
+		{Col("age").Between(0, 150), Row{"age": int32(20)}, true},
+		{Col("age").Between(0, 150), Row{"age": int32(200)}, false},
+		{Col("age").GT(1.5), Row{"age": uint8(2)}, true},
+		{Col("age").GTE(0), Row{"age": (*int32)(nil)}, true},
+		{Col("age").GTE(0).And(Col("age").NotNull()), Row{"age": (*int32)(nil)}, false},
+		{Length(Col("name")).GTE(3), Row{"name": &name}, true},
+		{Length(Col("name")).GTE(3), Row{"name": "伊"}, false},
+		{Col("name").In("alice", "bob"), Row{"name": &name}, true},
+		{Col("name").NotIn("alice", "bob"), Row{"name": &name}, false},
+		{Col("name").Like("b_b%"), Row{"name": "bobby"}, true},
+		{Col("name").Like(`100\%`), Row{"name": "1000"}, false},
+		{Col("name").IsNull().Or(Col("name").NEQ("admin")), Row{"name": none}, true},
+		{Col("start").LT(Col("end")), Row{"start": time.Unix(1, 0), "end": time.Unix(2, 0)}, true},
+		{Col("active").EQ(true), Row{"active": false}, false},
+	}
+	for i, c := range cases {
+		got, err := c.cond.Eval(c.row)
+		if err != nil {
+			t.Fatalf("第%d个条件计算失败: %v", i, err)
+		}
+		if got != c.want {
+			t.Fatalf("第%d个条件的结果不正确: %s 期望 %v", i, c.cond.SQL(dialect.PostgreSQL), c.want)
+		}
+	}
+	if _, err := Col("age").GT(0).Eval(Row{}); err == nil {
+		t.Fatal("行中没有列时应返回错误")
+	}
+	if _, err := Col("name").GT(0).Eval(Row{"name": "a"}); err == nil {
+		t.Fatal("类型不同的值比较时应返回错误")
+	}
+}
+
+func TestCondJSON(t *testing.T) {
+	cond := Col("age").Between(0, 150).Or(Col("name").In("a", "b"))
+	b, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	parsed, err := Parse(b)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if parsed.SQL(dialect.PostgreSQL) != cond.SQL(dialect.PostgreSQL) {
+		t.Fatalf("解析后的条件不一致: %s", parsed.SQL(dialect.PostgreSQL))
+	}
+	invalid := []string{
+		`{"op":"column","name":"age"}`,
+		`{"op":">","args":[{"op":"column","name":"age"}]}`,
+		`{"op":"unknown"}`,
+		`{"op":"and","args":[{"op":"column","name":"a"},{"op":"column","name":"b"}]}`,
+	}
+	for _, s := range invalid {
+		if _, err := Parse([]byte(s)); err == nil {
+			t.Fatalf("解析不完整的条件时应返回错误: %s", s)
+		}
+	}
+}
+
+func TestConstraintValidate(t *testing.T) {
+	c := MustParse("chk_users_age", `{"op":">=","args":[{"op":"column","name":"age"},{"op":"value","value":0}]}`)
+	if err := c.Validate("users", Row{"age": 1}); err != nil {
+		t.Fatalf("满足约束时不应返回错误: %v", err)
+	}
+	err := c.Validate("users", Row{"age": -1})
+	code, ok := err.(interface{ Code() string })
+	if !ok || code.Code() != entity.Err_0100030014.Code() {
+		t.Fatalf("不满足约束时应返回Err_0100030014: %v", err)
+	}
+	if desc := c.Descriptor(); desc.Name != "chk_users_age" || len(desc.Expr) == 0 {
+		t.Fatalf("约束的描述不正确: %+v", desc)
+	}
+}
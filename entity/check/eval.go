@@ -0,0 +1,384 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// render 生成节点的SQL，条件都会加上括号。
+//
+// Params:
+//
+//   - n: 节点。
+//   - d: 数据库类型。
+func render(n *node, d dialect.DbDriver) string {
+	switch n.Op {
+	case opColumn:
+		if d == dialect.MySQL {
+			return "`" + strings.ReplaceAll(n.Name, "`", "``") + "`"
+		}
+		return `"` + strings.ReplaceAll(n.Name, `"`, `""`) + `"`
+	case opValue:
+		v, err := n.value()
+		if err != nil {
+			return "NULL"
+		}
+		switch v := v.(type) {
+		case string:
+			return entity.QuoteLiteral(v)
+		case bool:
+			if v {
+				return "TRUE"
+			}
+			return "FALSE"
+		case json.Number:
+			return v.String()
+		}
+		return "NULL"
+	case opLength:
+		if d == dialect.MySQL {
+			return "CHAR_LENGTH(" + render(n.Args[0], d) + ")"
+		}
+		return "length(" + render(n.Args[0], d) + ")"
+	case opEQ, opNEQ, opGT, opGTE, opLT, opLTE:
+		return "(" + render(n.Args[0], d) + " " + n.Op + " " + render(n.Args[1], d) + ")"
+	case opLike:
+		return "(" + render(n.Args[0], d) + " LIKE " + render(n.Args[1], d) + ")"
+	case opIn:
+		vs := make([]string, len(n.Args)-1)
+		for i, a := range n.Args[1:] {
+			vs[i] = render(a, d)
+		}
+		return "(" + render(n.Args[0], d) + " IN (" + strings.Join(vs, ", ") + "))"
+	case opIsNull:
+		return "(" + render(n.Args[0], d) + " IS NULL)"
+	case opNotNull:
+		return "(" + render(n.Args[0], d) + " IS NOT NULL)"
+	case opAnd, opOr:
+		cs := make([]string, len(n.Args))
+		for i, a := range n.Args {
+			cs[i] = render(a, d)
+		}
+		return "(" + strings.Join(cs, " "+strings.ToUpper(n.Op)+" ") + ")"
+	case opNot:
+		return "(NOT " + render(n.Args[0], d) + ")"
+	}
+	return ""
+}
+
+// Eval 在Go中计算条件，和SQL一样使用三值逻辑，结果为NULL时返回true。
+//
+// Params:
+//
+//   - row: 每一列的值。
+func (c Cond) Eval(row Row) (bool, error) {
+	if c.n == nil {
+		return true, nil
+	}
+	v, err := evalCond(c.n, row)
+	if err != nil {
+		return false, err
+	}
+	return v == nil || *v, nil
+}
+
+// evalCond 计算条件，返回nil表示NULL。
+func evalCond(n *node, row Row) (*bool, error) {
+	switch n.Op {
+	case opEQ, opNEQ, opGT, opGTE, opLT, opLTE:
+		l, err := evalExpr(n.Args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		r, err := evalExpr(n.Args[1], row)
+		if err != nil {
+			return nil, err
+		}
+		if l == nil || r == nil {
+			return nil, nil
+		}
+		cmp, err := compare(l, r, n.Op == opEQ || n.Op == opNEQ)
+		if err != nil {
+			return nil, err
+		}
+		var b bool
+		switch n.Op {
+		case opEQ:
+			b = cmp == 0
+		case opNEQ:
+			b = cmp != 0
+		case opGT:
+			b = cmp > 0
+		case opGTE:
+			b = cmp >= 0
+		case opLT:
+			b = cmp < 0
+		case opLTE:
+			b = cmp <= 0
+		}
+		return &b, nil
+	case opIn:
+		l, err := evalExpr(n.Args[0], row)
+		if err != nil || l == nil {
+			return nil, err
+		}
+		null := false
+		for _, a := range n.Args[1:] {
+			r, err := evalExpr(a, row)
+			if err != nil {
+				return nil, err
+			}
+			if r == nil {
+				null = true
+				continue
+			}
+			cmp, err := compare(l, r, true)
+			if err != nil {
+				return nil, err
+			}
+			if cmp == 0 {
+				return boolPtr(true), nil
+			}
+		}
+		if null {
+			return nil, nil
+		}
+		return boolPtr(false), nil
+	case opLike:
+		l, err := evalExpr(n.Args[0], row)
+		if err != nil || l == nil {
+			return nil, err
+		}
+		p, err := evalExpr(n.Args[1], row)
+		if err != nil || p == nil {
+			return nil, err
+		}
+		s, ok1 := l.(string)
+		pattern, ok2 := p.(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("check: LIKE requires strings, got %T and %T", l, p)
+		}
+		return boolPtr(likeRegexp(pattern).MatchString(s)), nil
+	case opIsNull, opNotNull:
+		v, err := evalExpr(n.Args[0], row)
+		if err != nil {
+			return nil, err
+		}
+		return boolPtr((v == nil) == (n.Op == opIsNull)), nil
+	case opAnd, opOr:
+		// AND中有false时为false，OR中有true时为true，否则有NULL时为NULL。
+		null := false
+		for _, a := range n.Args {
+			v, err := evalCond(a, row)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				null = true
+			} else if *v == (n.Op == opOr) {
+				return v, nil
+			}
+		}
+		if null {
+			return nil, nil
+		}
+		return boolPtr(n.Op == opAnd), nil
+	case opNot:
+		v, err := evalCond(n.Args[0], row)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return boolPtr(!*v), nil
+	}
+	return nil, fmt.Errorf("check: unknown operator %q", n.Op)
+}
+
+// evalExpr 计算值，返回nil表示NULL，其他值为int64、uint64、float64、string、bool或者time.Time。
+func evalExpr(n *node, row Row) (any, error) {
+	switch n.Op {
+	case opColumn:
+		v, ok := row[n.Name]
+		if !ok {
+			return nil, fmt.Errorf("check: column %s is missing", n.Name)
+		}
+		return normalize(v)
+	case opValue:
+		v, err := n.value()
+		if err != nil {
+			return nil, err
+		}
+		if num, ok := v.(json.Number); ok {
+			return parseNumber(num.String())
+		}
+		return v, nil
+	case opLength:
+		v, err := evalExpr(n.Args[0], row)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("check: length requires a string, got %T", v)
+		}
+		return int64(utf8.RuneCountInString(s)), nil
+	}
+	return nil, fmt.Errorf("check: operator %q is not a value", n.Op)
+}
+
+// normalize 解引用指针，并把数字统一为int64、uint64或者float64。
+func normalize(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		return t, nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+// parseNumber 解析字面量中的数字。
+func parseNumber(s string) (any, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// compare 比较两个不为NULL的值，返回-1、0或者1。
+//
+// Params:
+//
+//   - l: 左边的值。
+//   - r: 右边的值。
+//   - eq: 是否只比较相等，布尔值只能比较相等。
+func compare(l, r any, eq bool) (int, error) {
+	switch l := l.(type) {
+	case string:
+		if r, ok := r.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case bool:
+		if r, ok := r.(bool); ok && eq {
+			if l == r {
+				return 0, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if r, ok := r.(time.Time); ok {
+			return l.Compare(r), nil
+		}
+	case int64, uint64, float64:
+		switch r.(type) {
+		case int64, uint64, float64:
+			return compareNumber(l, r), nil
+		}
+	}
+	return 0, fmt.Errorf("check: can not compare %T with %T", l, r)
+}
+
+// compareNumber 比较两个数字，都是整数时按照整数比较，否则按照float64比较。
+func compareNumber(l, r any) int {
+	li, lInt := l.(int64)
+	ri, rInt := r.(int64)
+	lu, lUint := l.(uint64)
+	ru, rUint := r.(uint64)
+	switch {
+	case lInt && rInt:
+		return cmpOrdered(li, ri)
+	case lUint && rUint:
+		return cmpOrdered(lu, ru)
+	case lInt && rUint:
+		if li < 0 {
+			return -1
+		}
+		return cmpOrdered(uint64(li), ru)
+	case lUint && rInt:
+		if ri < 0 {
+			return 1
+		}
+		return cmpOrdered(lu, uint64(ri))
+	}
+	return cmpOrdered(toFloat(l), toFloat(r))
+}
+
+func cmpOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v any) float64 {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// likeRegexp 把LIKE的模式转换为正则表达式。
+func likeRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -2,11 +2,13 @@ package internal
 
 import (
 	"log"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zodileap/taurus_go/entity/codegen"
 	"github.com/zodileap/taurus_go/entity/codegen/gen"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
 )
 
 // GenerateCmd 生成Schema的资源文件，通过运行`github.com/zodileap/taurus_go/entity/cmd generate`调用。
@@ -18,6 +20,7 @@ func GenerateCmd() *cobra.Command {
 	var (
 		templates   []string
 		packageName string
+		configFile  string
 		config      gen.Config
 		cmd         = &cobra.Command{
 			Use:     "generate [flags] path",
@@ -30,6 +33,15 @@ func GenerateCmd() *cobra.Command {
 					packageName = "entity"
 				}
 				config.PackageName = packageName
+				if err := gen.ValidDocsFormats(config.Docs); err != nil {
+					log.Fatalln(err)
+				}
+				if err := gen.ValidSpecFormats(config.Specs); err != nil {
+					log.Fatalln(err)
+				}
+				if err := load.ValidLoader(config.Loader); err != nil {
+					log.Fatalln(err)
+				}
 
 				// 当前只使用位置参数中的 path，其他生成选项通过 flags 组装到 exts 中。
 				exts := []codegen.Extra{}
@@ -56,6 +68,14 @@ func GenerateCmd() *cobra.Command {
 						log.Fatalln("unsupported template type", typ)
 					}
 				}
+				// 项目配置文件不存在时，只有显式指定了--config才返回错误。
+				if _, err := os.Stat(configFile); err == nil || cmd.Flags().Changed("config") {
+					project, err := codegen.LoadProjectConfig(configFile)
+					if err != nil {
+						log.Fatalln(err)
+					}
+					exts = append(exts, project.Extra())
+				}
 				// 执行代码生成
 				if err := codegen.Generate(path[0], &config, exts...); err != nil {
 					log.Fatalln(err)
@@ -65,5 +85,15 @@ func GenerateCmd() *cobra.Command {
 	)
 	cmd.Flags().StringSliceVarP(&templates, "template", "t", nil, "external templates to execute, format: dir=dirpath, file=filepath, glob=globpath")
 	cmd.Flags().StringVarP(&packageName, "package", "p", "", "package name for generated code, defaults to entity")
+	cmd.Flags().StringSliceVar(&config.Docs, "docs", nil, "generate database docs into the docs directory, formats: markdown, html, mermaid, dot")
+	cmd.Flags().Lookup("docs").NoOptDefVal = "markdown,mermaid"
+	cmd.Flags().StringSliceVar(&config.Specs, "spec", nil, "generate JSON Schema and OpenAPI components into the spec directory, formats: jsonschema, openapi")
+	cmd.Flags().Lookup("spec").NoOptDefVal = "jsonschema,openapi"
+	cmd.Flags().BoolVar(&config.Proto, "proto", false, "generate protobuf messages, converters and gRPC CRUD services of the entities")
+	cmd.Flags().BoolVar(&config.DryRun, "dry-run", false, "print a unified diff of the generated code instead of writing it")
+	cmd.Flags().BoolVar(&config.Check, "check", false, "fail if the generated code is out of date, without writing it")
+	cmd.Flags().BoolVar(&config.Force, "force", false, "regenerate all the code without the incremental cache")
+	cmd.Flags().StringVar(&configFile, "config", codegen.ProjectConfigFile, "project config file listing the codegen plugins to run")
+	cmd.Flags().StringVar(&config.Loader, "loader", load.LoaderRun, "how to run the schema loader: run (go run each time) or binary (build once and reuse the cached binary, works offline and when cross-compiling)")
 	return cmd
 }
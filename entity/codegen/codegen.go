@@ -68,7 +68,7 @@ func Generate(entityPath string, cfg *gen.Config, options ...Extra) error {
 //   - 代码构建器
 //   - 错误信息
 func LoadBuilder(entityPath string, cfg *gen.Config) (*gen.Builder, error) {
-	builder, err := (&load.Config{Path: entityPath, BuildFlags: cfg.BuildFlags}).Load()
+	builder, err := (&load.Config{Path: entityPath, BuildFlags: cfg.BuildFlags, Loader: cfg.Loader}).Load()
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +96,10 @@ func LoadBuilder(entityPath string, cfg *gen.Config) (*gen.Builder, error) {
 //   - entityPath: entity package的路径
 //   - cfg: 代码生成的配置
 func generate(entityPath string, cfg *gen.Config) error {
+	// schema package、模版和配置都没有变化时，不需要运行加载程序。
+	if gen.UpToDate(cfg, entityPath) {
+		return nil
+	}
 	builder, err := LoadBuilder(entityPath, cfg)
 	if err != nil {
 		return err
@@ -240,7 +240,13 @@ func ValidSchemaName(name string) error {
 func generate(t *Builder) error {
 	var (
 		assets asset.Assets
+		prev   = newCache()
+		next   = newCache()
+		config = t.configHash()
 	)
+	if t.cacheEnabled() {
+		prev = readCache(t.Config.Target)
+	}
 	// 获取模版。
 	// 为每个节点生成代码：
 	for _, n := range t.Nodes {
@@ -255,6 +261,31 @@ func generate(t *Builder) error {
 			}
 			assets.Add(filepath.Join(t.Config.Target, tmpl.Format(n)), b.Bytes())
 		}
+		for _, tmpl := range DocsTemplates {
+			if !t.docsEnabled(tmpl.Name) {
+				continue
+			}
+			assets.AddDir(filepath.Join(t.Config.Target, filepath.Dir(tmpl.Format(n))))
+			b := bytes.NewBuffer(nil)
+			if err := templates.ExecuteTemplate(b, tmpl.Name, n); err != nil {
+				return fmt.Errorf("execute template %q: %w", tmpl.Name, err)
+			}
+			assets.Add(filepath.Join(t.Config.Target, tmpl.Format(n)), b.Bytes())
+		}
+		for _, tmpl := range ProtoTemplates {
+			if !t.Proto {
+				continue
+			}
+			assets.AddDir(filepath.Join(t.Config.Target, filepath.Dir(tmpl.Format(n))))
+			b := bytes.NewBuffer(nil)
+			if err := templates.ExecuteTemplate(b, tmpl.Name, n); err != nil {
+				return fmt.Errorf("execute template %q: %w", tmpl.Name, err)
+			}
+			assets.Add(filepath.Join(t.Config.Target, tmpl.Format(n)), b.Bytes())
+		}
+		if err := t.genSpecs(&assets, t.Config.Target, n); err != nil {
+			return err
+		}
 		for _, ext := range extend {
 			b := bytes.NewBuffer(nil)
 			if err := templates.ExecuteTemplate(b, ext.Tmpl.Name, n); err != nil {
@@ -282,12 +313,24 @@ func generate(t *Builder) error {
 				return err
 			}
 			assets.AddDir(filepath.Join(t.Config.Target, ei.Dir()))
+			// entity没有变化时，不需要重新生成。
+			key, hash := n.Database.Name+"/"+entityName, entityHash(e, n.Database, config)
+			if prev.keep(next, t.Config.Target, key, hash) {
+				continue
+			}
+			cached := &cacheEntity{Hash: hash}
 			for _, tmpl := range EntityTemplates {
+				var info any = ei
+				if tmpl.Skip != nil && tmpl.Skip(&info) {
+					continue
+				}
 				b := bytes.NewBuffer(nil)
 				if err := templates.ExecuteTemplate(b, tmpl.Name, ei); err != nil {
 					return fmt.Errorf("execute template %q: %w", tmpl.Name, err)
 				}
-				assets.Add(filepath.Join(t.Config.Target, tmpl.Format(ei)), b.Bytes())
+				path := filepath.Join(t.Config.Target, tmpl.Format(ei))
+				assets.Add(path, b.Bytes())
+				cached.Files = append(cached.Files, t.relPath(path))
 			}
 			for _, field := range e.Fields {
 				fieldName := field.AttrName
@@ -301,10 +344,13 @@ func generate(t *Builder) error {
 						if err := templates.ExecuteTemplate(b, tmpl.Name, fi); err != nil {
 							return fmt.Errorf("execute template %q: %w", tmpl.Name, err)
 						}
-						assets.Add(filepath.Join(t.Config.Target, tmpl.Format(ei)), b.Bytes())
+						path := filepath.Join(t.Config.Target, tmpl.Format(ei))
+						assets.Add(path, b.Bytes())
+						cached.Files = append(cached.Files, t.relPath(path))
 					}
 				}
 			}
+			next.Entities[key] = cached
 		}
 	}
 	templates, _, _ := t.templates("")
@@ -335,6 +381,10 @@ func generate(t *Builder) error {
 		}
 	}
 
+	if err := t.runPlugins(&assets); err != nil {
+		return err
+	}
+
 	// 清理功能
 	// for _, f := range AllFeatures {
 	// 	if f.cleanup == nil || t.featureEnabled(f) {
@@ -344,18 +394,7 @@ func generate(t *Builder) error {
 	// 		return fmt.Errorf("cleanup %q feature assets: %w", f.Name, err)
 	// 	}
 	// }
-	// 写入和格式化生成的代码。
-	if err := assets.Write(); err != nil {
-		return err
-	}
-	// 清理旧的节点和模板文件。
-	cleanOldNodes(assets, t.Config.Target)
-	for _, n := range deletedTemplates {
-		if err := os.Remove(filepath.Join(t.Target, n)); err != nil && !os.IsNotExist(err) {
-			log.Printf("remove old file %s: %s\n", filepath.Join(t.Target, n), err)
-		}
-	}
-	return assets.Format()
+	return t.flush(assets, prev, next)
 }
 
 // catch 错误处理，如果是tableError类型错误，会抛出panic
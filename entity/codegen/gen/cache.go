@@ -0,0 +1,408 @@
+package gen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zodileap/taurus_go/asset"
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+	"golang.org/x/tools/imports"
+)
+
+const (
+	// CacheFile 增量生成的缓存文件，保存在Target目录中。
+	CacheFile = ".entity_cache.json"
+	// cacheVersion 缓存的版本，缓存的结构或者生成的逻辑变化时需要修改。
+	cacheVersion = "1"
+)
+
+// diffOutput dry-run时输出diff的位置。
+var diffOutput io.Writer = os.Stdout
+
+type (
+	// genCache 增量生成的缓存，记录了上一次生成时的输入和生成的文件。
+	genCache struct {
+		// Version 缓存的版本。
+		Version string `json:"version"`
+		// Inputs schema package、模版和配置的hash，没有变化时不需要重新加载entity。
+		Inputs string `json:"inputs"`
+		// Entities entity的hash和生成的文件，key是数据库名称和entity的AttrName。
+		Entities map[string]*cacheEntity `json:"entities"`
+		// Files 生成的文件，key是相对于Target的路径。
+		Files map[string]*cacheFile `json:"files"`
+	}
+
+	// cacheEntity entity的缓存。
+	cacheEntity struct {
+		// Hash entity、模版和配置的hash。
+		Hash string `json:"hash"`
+		// Files entity生成的文件，相对于Target的路径。
+		Files []string `json:"files"`
+	}
+
+	// cacheFile 生成的文件的缓存。
+	cacheFile struct {
+		// Raw 模版生成的内容的hash。
+		Raw string `json:"raw"`
+		// Sum 格式化后写入文件的内容的hash。
+		Sum string `json:"sum"`
+	}
+)
+
+// UpToDate 检查生成的代码是否是最新的，如果schema package、模版和配置都没有变化，
+// 并且生成的文件没有被修改，则不需要重新加载entity和生成代码。
+// dry-run、check和force模式以及设置了Hooks或Plugins时总是返回false。
+//
+// Params:
+//
+//   - c: 代码生成的配置。
+//   - schemaPath: schema package的路径。
+//
+// Returns:
+//
+//	0: 生成的代码是否是最新的。
+func UpToDate(c *Config, schemaPath string) bool {
+	inputs, err := c.inputsHash(schemaPath)
+	if err != nil {
+		return false
+	}
+	c.inputs = inputs
+	if !c.cacheEnabled() {
+		return false
+	}
+	prev := readCache(c.Target)
+	if prev.Inputs != inputs || len(prev.Files) == 0 {
+		return false
+	}
+	for rel, f := range prev.Files {
+		if !f.fresh(filepath.Join(c.Target, rel)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheEnabled 是否使用缓存，见[Config.cacheable]。
+func (c *Config) cacheEnabled() bool {
+	return !c.DryRun && !c.Check && !c.Force && c.cacheable()
+}
+
+// cacheable 生成的代码是否只依赖schema、模版和配置。Hooks可能会修改生成的代码，
+// 插件生成的代码依赖插件程序，所以设置了Hooks或Plugins时不使用缓存。
+func (c *Config) cacheable() bool {
+	return len(c.Hooks) == 0 && len(c.Plugins) == 0
+}
+
+// inputsHash 计算schema package、模版和配置的hash。
+//
+// Params:
+//
+//   - schemaPath: schema package的路径。
+func (c *Config) inputsHash(schemaPath string) (string, error) {
+	abs, err := filepath.Abs(schemaPath)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", cacheVersion, abs, c.configHash())
+	err = filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(b))
+		h.Write(b)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	// 依赖的版本变化时，例如升级了taurus_go，也需要重新生成。
+	if mod := findGoMod(abs); mod != "" {
+		for _, name := range []string{"go.mod", "go.sum"} {
+			if b, err := os.ReadFile(filepath.Join(mod, name)); err == nil {
+				h.Write(b)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// configHash 计算模版和配置的hash，模版包括内置的模版和外部的模版。
+func (c *Config) configHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00", c.PackageName, c.Target, c.Package, c.Header, strings.Join(c.BuildFlags, " "))
+	fmt.Fprintf(h, "%v\x00%v\x00%v\x00%v\x00", c.ExtraCodes, c.Docs, c.Specs, c.Proto)
+	_ = fs.WalkDir(templateDir, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := templateDir.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(b))
+		h.Write(b)
+		return nil
+	})
+	for _, ext := range c.Templates {
+		fmt.Fprintf(h, "%v\x00", ext.TargetPaths)
+		for _, t := range ext.Tmpl.Templates() {
+			if t.Tree != nil {
+				fmt.Fprintf(h, "%s\x00%s\x00", t.Name(), t.Tree.Root.String())
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// entityHash 计算entity的hash，entity生成的代码只依赖entity、数据库的名称和类型、模版和配置。
+//
+// Params:
+//
+//   - e: entity。
+//   - db: entity所在的数据库。
+//   - config: 模版和配置的hash，见[Config.configHash]。
+func entityHash(e *load.Entity, db *load.Database, config string) string {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", cacheVersion, db.Name, db.Type, config)
+	h.Write(b)
+	// 字段关联的外部模版只记录了路径，需要加上模版的内容。
+	for _, f := range e.Fields {
+		for _, path := range f.Templates {
+			if tb, err := os.ReadFile(path); err == nil {
+				h.Write(tb)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCache 读取Target目录中的缓存，没有缓存或者缓存的版本不同时返回空的缓存。
+//
+// Params:
+//
+//   - target: 目标目录。
+func readCache(target string) *genCache {
+	c := newCache()
+	b, err := os.ReadFile(filepath.Join(target, CacheFile))
+	if err != nil {
+		return c
+	}
+	prev := &genCache{}
+	if err := json.Unmarshal(b, prev); err != nil || prev.Version != cacheVersion {
+		return c
+	}
+	if prev.Entities == nil {
+		prev.Entities = map[string]*cacheEntity{}
+	}
+	if prev.Files == nil {
+		prev.Files = map[string]*cacheFile{}
+	}
+	return prev
+}
+
+// newCache 创建一个空的缓存。
+func newCache() *genCache {
+	return &genCache{
+		Version:  cacheVersion,
+		Entities: map[string]*cacheEntity{},
+		Files:    map[string]*cacheFile{},
+	}
+}
+
+// write 把缓存写入Target目录。
+//
+// Params:
+//
+//   - target: 目标目录。
+func (c *genCache) write(target string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(target, CacheFile), append(b, '\n'), 0644)
+}
+
+// keep 如果entity没有变化，并且生成的文件没有被修改，则把上一次的缓存保留到next中。
+//
+// Params:
+//
+//   - next: 这一次生成的缓存。
+//   - target: 目标目录。
+//   - key: entity的key。
+//   - hash: entity的hash。
+//
+// Returns:
+//
+//	0: entity是否没有变化，没有变化时不需要重新生成。
+func (c *genCache) keep(next *genCache, target, key, hash string) bool {
+	e, ok := c.Entities[key]
+	if !ok || hash == "" || e.Hash != hash {
+		return false
+	}
+	for _, rel := range e.Files {
+		f, ok := c.Files[rel]
+		if !ok || !f.fresh(filepath.Join(target, rel)) {
+			return false
+		}
+	}
+	next.Entities[key] = e
+	for _, rel := range e.Files {
+		next.Files[rel] = c.Files[rel]
+	}
+	return true
+}
+
+// fresh 文件是否存在，并且内容和上一次生成时相同。
+//
+// Params:
+//
+//   - path: 文件的路径。
+func (f *cacheFile) fresh(path string) bool {
+	b, err := os.ReadFile(path)
+	return err == nil && f.Sum == hashBytes(b)
+}
+
+// flush 写入内容变化的文件，并更新缓存。
+// dry-run模式只输出diff，check模式在文件不是最新时返回错误，这两种模式都不会写入文件。
+//
+// Params:
+//
+//   - assets: 生成的文件。
+//   - prev: 上一次生成的缓存。
+//   - next: 这一次生成的缓存，已经包含了没有变化的entity。
+func (t *Builder) flush(assets asset.Assets, prev, next *genCache) error {
+	if t.DryRun || t.Check {
+		return t.diff(assets)
+	}
+	changed := asset.Assets{Dirs: assets.Dirs}
+	for path, b := range assets.Files {
+		rel := t.relPath(path)
+		raw := hashBytes(b)
+		if f, ok := prev.Files[rel]; ok && f.Raw == raw && f.fresh(path) {
+			next.Files[rel] = f
+			continue
+		}
+		changed.Add(path, b)
+		next.Files[rel] = &cacheFile{Raw: raw}
+	}
+	// 写入和格式化生成的代码。
+	if err := changed.Write(); err != nil {
+		return err
+	}
+	// 清理旧的节点和模板文件。
+	cleanOldNodes(assets, t.Config.Target)
+	for _, n := range deletedTemplates {
+		if err := os.Remove(filepath.Join(t.Target, n)); err != nil && !os.IsNotExist(err) {
+			log.Printf("remove old file %s: %s\n", filepath.Join(t.Target, n), err)
+		}
+	}
+	if err := changed.Format(); err != nil {
+		return err
+	}
+	for path := range changed.Files {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		next.Files[t.relPath(path)].Sum = hashBytes(b)
+	}
+	if !t.cacheable() {
+		return nil
+	}
+	next.Inputs = t.inputs
+	if err := next.write(t.Config.Target); err != nil {
+		log.Printf("write codegen cache: %s\n", err)
+	}
+	return nil
+}
+
+// diff 比较生成的文件和已经存在的文件，dry-run模式输出unified diff，
+// check模式在有文件需要更新时返回错误。
+//
+// Params:
+//
+//   - assets: 生成的文件。
+func (t *Builder) diff(assets asset.Assets) error {
+	paths := make([]string, 0, len(assets.Files))
+	for path := range assets.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var stale []string
+	for _, path := range paths {
+		b := assets.Files[path]
+		if filepath.Ext(path) == ".go" {
+			src, err := imports.Process(path, b, nil)
+			if err != nil {
+				return asset.Err_0200020002.Sprintf(path, err)
+			}
+			b = src
+		}
+		old, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err == nil && bytes.Equal(old, b) {
+			continue
+		}
+		rel := t.relPath(path)
+		stale = append(stale, rel)
+		if t.DryRun {
+			fmt.Fprint(diffOutput, unifiedDiff(filepath.ToSlash(rel), old, b))
+		}
+	}
+	if t.Check && len(stale) > 0 {
+		return entity.Err_0100020024.Sprintf(strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// relPath 返回相对于Target的路径。
+func (t *Builder) relPath(path string) string {
+	rel, err := filepath.Rel(t.Config.Target, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// hashBytes 返回内容的sha256。
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// findGoMod 从dir开始向上查找go.mod所在的目录，没有找到时返回空字符串。
+func findGoMod(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,67 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+func TestCacheKeep(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "e_user.go"), []byte("package entity\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prev := newCache()
+	prev.Entities["shop/user"] = &cacheEntity{Hash: "h1", Files: []string{"e_user.go"}}
+	prev.Files["e_user.go"] = &cacheFile{Raw: "r", Sum: hashBytes([]byte("package entity\n"))}
+	if err := prev.write(dir); err != nil {
+		t.Fatalf("写入缓存失败: %v", err)
+	}
+	prev = readCache(dir)
+
+	next := newCache()
+	if !prev.keep(next, dir, "shop/user", "h1") {
+		t.Fatal("entity和文件都没有变化时应该使用缓存")
+	}
+	if next.Files["e_user.go"] == nil {
+		t.Fatal("使用缓存时应保留文件的缓存")
+	}
+	if prev.keep(newCache(), dir, "shop/user", "h2") {
+		t.Fatal("entity变化时不应使用缓存")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "e_user.go"), []byte("package entity // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if prev.keep(newCache(), dir, "shop/user", "h1") {
+		t.Fatal("生成的文件被修改时不应使用缓存")
+	}
+}
+
+func TestEntityHash(t *testing.T) {
+	db := &load.Database{Name: "shop", Type: "postgres"}
+	e := &load.Entity{Name: "UserEntity", AttrName: "user", Comment: "users"}
+	h := entityHash(e, db, "c")
+	if h != entityHash(e, db, "c") {
+		t.Fatal("相同的entity的hash应相同")
+	}
+	if h == entityHash(e, db, "d") {
+		t.Fatal("配置变化时entity的hash应变化")
+	}
+	e.Comment = "registered users"
+	if h == entityHash(e, db, "c") {
+		t.Fatal("entity变化时hash应变化")
+	}
+}
+
+func TestCacheEnabled(t *testing.T) {
+	if !(&Config{}).cacheEnabled() {
+		t.Fatal("默认应使用缓存")
+	}
+	for _, c := range []*Config{{DryRun: true}, {Check: true}, {Force: true}} {
+		if c.cacheEnabled() {
+			t.Fatalf("dry-run、check和force模式不应使用缓存: %+v", c)
+		}
+	}
+}
@@ -39,6 +39,9 @@ type Config struct {
 	// Hooks 可选的hook列表，用于代码生成前、后在表上执行
 	Hooks []Hook
 
+	// Plugins 在内置的模版生成之后执行的插件，见[Plugin]。
+	Plugins []Plugin
+
 	// Templates 外部传入的template
 	Templates []ExtTemplate
 
@@ -46,4 +49,31 @@ type Config struct {
 	ExtraCodes []string
 
 	ExtTemplatesTargetPaths []string
+
+	// Docs 需要生成的文档格式，见[DocsFormats]，为空时不生成文档。
+	// 文档会生成到Target下的docs目录中。
+	Docs []string
+
+	// Specs 需要生成的接口描述文档格式，见[SpecFormats]，为空时不生成。
+	// 文档会生成到Target下的spec目录中，每次生成代码时都会和entity的定义同步。
+	Specs []string
+
+	// Proto 是否生成protobuf和gRPC的代码。protobuf生成到Target下的proto/<database>目录中，
+	// 需要用protoc编译；Go的转换函数和CRUD服务生成到Target下的grpc/<database>目录中。
+	Proto bool
+
+	// DryRun 只输出生成的代码和已有代码的unified diff，不写入文件。
+	DryRun bool
+
+	// Check 生成的代码不是最新的时候返回错误，不写入文件，用于CI中检查生成的代码是否已经提交。
+	Check bool
+
+	// Force 不使用增量生成的缓存，重新生成全部的代码。
+	Force bool
+
+	// Loader 加载Schema的方式，"run"每次通过go run执行加载程序，"binary"缓存构建的加载程序，为空时使用"run"。
+	Loader string
+
+	// inputs schema package、模版和配置的hash，见[UpToDate]。
+	inputs string
 }
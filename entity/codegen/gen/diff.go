@@ -0,0 +1,192 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext unified diff中变化前后保留的行数。
+	diffContext = 3
+	// diffMaxEdits 计算diff时最多的编辑次数，超过后整个文件作为一次替换输出，避免占用过多内存。
+	diffMaxEdits = 4000
+)
+
+// diffOp diff中的一行，Kind为' '表示相同，'-'表示删除，'+'表示新增。
+type diffOp struct {
+	Kind byte
+	Line string
+}
+
+// unifiedDiff 返回文件修改前后的unified diff，内容相同时返回空字符串。
+//
+// Params:
+//
+//   - name: 文件名称。
+//   - old: 修改前的内容，为nil时表示新文件。
+//   - new: 修改后的内容。
+func unifiedDiff(name string, old, new []byte) string {
+	ops := diffLines(splitLines(string(old)), splitLines(string(new)))
+	var b strings.Builder
+	from := "a/" + name
+	if old == nil {
+		from = "/dev/null"
+	}
+	// o, n 是每一行之前修改前后的行数。
+	o, n := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		o[i+1], n[i+1] = o[i], n[i]
+		if op.Kind != '+' {
+			o[i+1]++
+		}
+		if op.Kind != '-' {
+			n[i+1]++
+		}
+	}
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].Kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		start, end := max(i-diffContext, 0), i
+		for {
+			for end < len(ops) && ops[end].Kind != ' ' {
+				end++
+			}
+			j := end
+			for j < len(ops) && ops[j].Kind == ' ' {
+				j++
+			}
+			if j < len(ops) && j-end <= 2*diffContext {
+				end = j
+				continue
+			}
+			end = min(end+diffContext, len(ops))
+			break
+		}
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ b/%s\n", from, name)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", diffRange(o[start], o[end]), diffRange(n[start], n[end]))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.Kind)
+			b.WriteString(op.Line)
+			if !strings.HasSuffix(op.Line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// diffRange 返回hunk头中的行范围。
+//
+// Params:
+//
+//   - from: hunk开始前的行数。
+//   - to: hunk结束时的行数。
+func diffRange(from, to int) string {
+	if to-from == 0 {
+		return fmt.Sprintf("%d,0", from)
+	}
+	if to-from == 1 {
+		return fmt.Sprintf("%d", from+1)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
+
+// splitLines 把文本分割为行，每行保留行尾的换行符。
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 用Myers算法计算两组行之间最短的编辑序列。
+//
+// Params:
+//
+//   - a: 修改前的行。
+//   - b: 修改后的行。
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		if d > diffMaxEdits {
+			return diffReplace(a, b)
+		}
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return diffBacktrack(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// diffBacktrack 根据Myers算法每一步的结果，从末尾回溯出编辑序列。
+func diffBacktrack(trace [][]int, a, b []string, offset int) []diffOp {
+	x, y := len(a), len(b)
+	var ops []diffOp
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// diffReplace 把全部的行作为一次替换。
+func diffReplace(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, l := range a {
+		ops = append(ops, diffOp{'-', l})
+	}
+	for _, l := range b {
+		ops = append(ops, diffOp{'+', l})
+	}
+	return ops
+}
@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	new := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\nk\n"
+	want := `--- a/x.go
++++ b/x.go
+@@ -2,9 +2,10 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+ i
+ j
++k
+`
+	if got := unifiedDiff("x.go", []byte(old), []byte(new)); got != want {
+		t.Fatalf("unified diff错误:\n%s", got)
+	}
+	if got := unifiedDiff("x.go", []byte(old), []byte(old)); got != "" {
+		t.Fatalf("内容相同时不应有diff:\n%s", got)
+	}
+	got := unifiedDiff("x.go", nil, []byte("a\nb"))
+	if !strings.HasPrefix(got, "--- /dev/null\n+++ b/x.go\n@@ -0,0 +1,2 @@\n+a\n+b\n\\ No newline at end of file\n") {
+		t.Fatalf("新文件的diff错误:\n%s", got)
+	}
+}
+
+func TestDiffLinesSeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, "line\n")
+		b = append(b, "line\n")
+	}
+	b[1], b[18] = "first\n", "last\n"
+	got := unifiedDiff("x", []byte(strings.Join(a, "")), []byte(strings.Join(b, "")))
+	if strings.Count(got, "@@ -") != 2 {
+		t.Fatalf("相距较远的修改应该分为两个hunk:\n%s", got)
+	}
+}
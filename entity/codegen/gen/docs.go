@@ -0,0 +1,120 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+// DocsFormats 可以生成的文档格式，key是格式名称，value是模版名称。
+var DocsFormats = map[string]string{
+	"markdown": "docs/markdown",
+	"html":     "docs/html",
+	"mermaid":  "docs/mermaid",
+	"dot":      "docs/dot",
+}
+
+// mermaidTypeReplacer 用于替换Mermaid中类型不支持的字符。
+var mermaidTypeReplacer = regexp.MustCompile(`[^A-Za-z0-9_\-\[\]()]`)
+
+// docsEnabled 是否需要生成模版对应格式的文档。
+//
+// Params:
+//
+//   - name: 文档的模版名称。
+func (c *Config) docsEnabled(name string) bool {
+	for _, f := range c.Docs {
+		if DocsFormats[f] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidDocsFormats 检查文档格式是否支持。
+//
+// Params:
+//
+//   - formats: 文档格式。
+func ValidDocsFormats(formats []string) error {
+	for _, f := range formats {
+		if _, ok := DocsFormats[f]; !ok {
+			return fmt.Errorf("unsupported docs format %q, supported formats: markdown, html, mermaid, dot", f)
+		}
+	}
+	return nil
+}
+
+// docsEscape 转义Markdown表格中的内容，"|"会被转义，换行会替换为<br>。
+func docsEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// docsKind 返回entity在数据库中的类型。
+func docsKind(e *load.Entity) string {
+	switch {
+	case e.View != nil && e.View.Materialized:
+		return "materialized view"
+	case e.View != nil:
+		return "view"
+	case e.Config.Partition != nil:
+		return "partitioned table"
+	default:
+		return "table"
+	}
+}
+
+// docsDefault 返回字段默认值的说明，序列会说明序列的名称或者生成的方式。
+func docsDefault(f *load.Field) string {
+	switch {
+	case f.Sequence.IsClient():
+		return fmt.Sprintf("%s (generated in Go)", f.Sequence.Mode)
+	case f.Sequence.Name != nil:
+		return fmt.Sprintf("sequence %s", *f.Sequence.Name)
+	case f.Generated != "":
+		return fmt.Sprintf("generated always as (%s) stored", f.Generated)
+	case f.Identity:
+		return "generated by default as identity"
+	default:
+		return f.DefaultValue
+	}
+}
+
+// docsKeys 返回字段的键，PK是主键，FK是外键，UK是唯一键，多个键用逗号分隔。
+func docsKeys(e *load.Entity, f *load.Field) string {
+	var keys []string
+	if f.Primary > 0 {
+		keys = append(keys, "PK")
+	}
+	for _, rel := range e.Relations {
+		if rel.Dependent.AttrName == e.AttrName && rel.Dependent.Field.AttrName == f.AttrName {
+			keys = append(keys, "FK")
+			break
+		}
+	}
+	if len(f.Uniques) > 0 {
+		keys = append(keys, "UK")
+	}
+	return strings.Join(keys, ", ")
+}
+
+// docsMermaidType 替换Mermaid中类型不支持的字符，例如numeric(10,2)中的逗号。
+func docsMermaidType(s string) string {
+	return mermaidTypeReplacer.ReplaceAllString(s, "_")
+}
+
+// docsMermaidRel 返回Mermaid中两个entity之间关系的符号，例如"||--o{"。
+func docsMermaidRel(rel *load.Relation) string {
+	left, right := "||", "||"
+	if rel.Principal.Rel == entity.M {
+		left = "}o"
+	}
+	if rel.Dependent.Rel == entity.M {
+		right = "o{"
+	}
+	return left + "--" + right
+}
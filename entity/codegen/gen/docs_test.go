@@ -0,0 +1,51 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+func TestValidDocsFormats(t *testing.T) {
+	if err := ValidDocsFormats([]string{"markdown", "html", "mermaid", "dot"}); err != nil {
+		t.Fatalf("合法的文档格式被误判: %v", err)
+	}
+	if err := ValidDocsFormats([]string{"pdf"}); err == nil {
+		t.Fatal("不支持的文档格式应返回错误")
+	}
+	c := &Config{Docs: []string{"mermaid"}}
+	if !c.docsEnabled("docs/mermaid") || c.docsEnabled("docs/markdown") {
+		t.Fatal("只应生成选择的文档格式")
+	}
+}
+
+func TestDocsHelpers(t *testing.T) {
+	e := &load.Entity{AttrName: "blog"}
+	f := &load.Field{}
+	f.AttrName, f.Primary = "user_id", 1
+	e.Relations = []*load.Relation{{
+		Principal: load.RelationEntity{AttrName: "user", Rel: entity.O},
+		Dependent: load.RelationEntity{AttrName: "blog", Rel: entity.M, Field: f},
+	}}
+	if got := docsKeys(e, f); got != "PK, FK" {
+		t.Fatalf("字段的键错误: %s", got)
+	}
+	if got := docsMermaidRel(e.Relations[0]); got != "||--o{" {
+		t.Fatalf("一对多关系的符号错误: %s", got)
+	}
+	if got := docsMermaidType("numeric(10,2)"); got != "numeric(10_2)" {
+		t.Fatalf("Mermaid类型替换错误: %s", got)
+	}
+	if got := docsEscape("a|b\nc"); got != `a\|b<br>c` {
+		t.Fatalf("Markdown转义错误: %s", got)
+	}
+	f.Generated = "price * quantity"
+	if got := docsDefault(f); got != "generated always as (price * quantity) stored" {
+		t.Fatalf("生成列的默认值错误: %s", got)
+	}
+	f.Generated, f.Identity = "", true
+	if got := docsDefault(f); got != "generated by default as identity" {
+		t.Fatalf("标识列的默认值错误: %s", got)
+	}
+}
@@ -20,6 +20,7 @@ var FuncMap = template.FuncMap{
 	"joinRequiredFields":        joinRequiredFields,
 	"joinFieldsString":          joinFieldsString,
 	"getPrimaryField":           getPrimaryField,
+	"getTenantField":            getTenantField,
 	"snakeCaseToLowerCamelCase": snakeCaseToLowerCamelCase,
 	"getRequiredFields":         getRequiredFields,
 	"getEntityRel":              getEntityRel,
@@ -30,9 +31,23 @@ var FuncMap = template.FuncMap{
 	"stringJoinIndexFields":     stringJoinIndexFields,
 	"stringJoinIndexColumns":    stringJoinIndexColumns,
 	"stringJoinQuotedColumns":   stringJoinQuotedColumns,
+	"stringJoinIndexDefs":       stringJoinIndexDefs,
 	"getUniqueGroups":           getUniqueGroups,
 	"getUniqueFieldGroups":      getUniqueFieldGroups,
 	"removeArrayBrackets":       removeArrayBrackets,
+	"docsEscape":                docsEscape,
+	"docsKind":                  docsKind,
+	"docsDefault":               docsDefault,
+	"docsKeys":                  docsKeys,
+	"docsMermaidType":           docsMermaidType,
+	"docsMermaidRel":            docsMermaidRel,
+	"protoPkg":                  protoPkg,
+	"protoType":                 protoType,
+	"protoGoType":               protoGoType,
+	"protoGoName":               protoGoName,
+	"protoZero":                 protoZero,
+	"protoNumber":               protoNumber,
+	"protoHasTimestamp":         protoHasTimestamp,
 }
 
 // joinFieldAttrNames 把字段的AttrName连接起来。
@@ -136,6 +151,27 @@ func getPrimaryField(fs []*load.Field) *load.Field {
 	return nil
 }
 
+// getTenantField 获取entity的租户字段。
+//
+// Params:
+//
+//   - e: entity。
+//
+// Returns:
+//
+//	0: 租户字段，没有设置租户字段时返回nil。
+func getTenantField(e *load.Entity) *load.Field {
+	if e.TenantField == "" {
+		return nil
+	}
+	for _, f := range e.Fields {
+		if f.Name == e.TenantField {
+			return f
+		}
+	}
+	return nil
+}
+
 // getLowerCamelCase 获取小驼峰命名，会清除snake_case的下划线。
 func snakeCaseToLowerCamelCase(a string) string {
 	// 分割字符串为单词数组
@@ -285,6 +321,26 @@ func stringJoinIndexColumns(fields []string) string {
 	return strings.Join(fields, ", ")
 }
 
+// stringJoinIndexDefs 把索引的列拼接为逗号分隔的列表，字段会添加引号，表达式会添加括号，
+// 并带上DESC和NULLS的排序。
+func stringJoinIndexDefs(cols []load.IndexColumn) string {
+	defs := make([]string, len(cols))
+	for i, c := range cols {
+		def := fmt.Sprintf("%q", c.Column)
+		if c.Expr {
+			def = "(" + c.Column + ")"
+		}
+		if c.Desc {
+			def += " DESC"
+		}
+		if c.Nulls != "" {
+			def += " NULLS " + c.Nulls
+		}
+		defs[i] = def
+	}
+	return strings.Join(defs, ", ")
+}
+
 // stringJoinQuotedColumns 将列名添加引号并用逗号连接
 func stringJoinQuotedColumns(fields []string) string {
 	quoted := make([]string, len(fields))
@@ -0,0 +1,234 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/zodileap/taurus_go/asset"
+	"github.com/zodileap/taurus_go/cmd"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+// PluginVersion 插件协议的版本，协议不兼容的修改会增加版本。
+const PluginVersion = "1"
+
+type (
+	// Plugin 代码生成的插件，在内置的模版生成之后执行，用于生成DTO、校验、API等额外的代码。
+	Plugin interface {
+		// Name 插件的名称，用于错误信息。
+		Name() string
+		// Generate 根据Builder生成额外的文件，文件和目录的路径是相对于Target的路径，
+		// 不能是绝对路径，也不能在Target之外。生成的Go文件会和内置的代码一起格式化。
+		Generate(*Builder) (*asset.Assets, error)
+	}
+
+	// PluginRequest 执行外部插件时，通过标准输入传给插件程序的JSON。
+	PluginRequest struct {
+		// Version 插件协议的版本，见[PluginVersion]。
+		Version string `json:"version"`
+		// Name 插件的名称。
+		Name string `json:"name"`
+		// Package 生成的代码的Go package路径。
+		Package string `json:"package"`
+		// PackageName 生成的代码的包名。
+		PackageName string `json:"packageName"`
+		// Target 生成的代码的目标路径。
+		Target string `json:"target"`
+		// Header 生成的文件的头部签名。
+		Header string `json:"header,omitempty"`
+		// Options 项目配置文件中插件的options。
+		Options map[string]string `json:"options,omitempty"`
+		// Databases 从Schema中加载的数据库。
+		Databases []*load.Database `json:"databases"`
+	}
+
+	// PluginResponse 外部插件通过标准输出返回的JSON。
+	PluginResponse struct {
+		// Files 生成的文件。
+		Files []PluginFile `json:"files,omitempty"`
+		// Error 插件出现的错误，不为空时不会写入任何文件。
+		Error string `json:"error,omitempty"`
+	}
+
+	// PluginFile 外部插件生成的文件。
+	PluginFile struct {
+		// Path 相对于Target的路径。
+		Path string `json:"path"`
+		// Content 文件的内容。
+		Content string `json:"content"`
+	}
+
+	// ExecPlugin 外部插件，每次生成代码时执行插件程序，通过标准输入和标准输出传递
+	// [PluginRequest]和[PluginResponse]，插件程序可以用任何语言编写，Go编写的插件可以使用[ServePlugin]。
+	ExecPlugin struct {
+		name    string
+		command []string
+		dir     string
+		options map[string]string
+	}
+)
+
+// NewExecPlugin 创建一个外部插件。
+//
+// Params:
+//
+//   - name: 插件的名称。
+//   - command: 执行插件程序的命令，例如["go", "run", "./tools/dto"]。
+//   - dir: 执行命令的目录，为空时使用当前目录。
+//   - options: 传给插件程序的options。
+//
+// Returns:
+//
+//	0: 外部插件。
+func NewExecPlugin(name string, command []string, dir string, options map[string]string) *ExecPlugin {
+	return &ExecPlugin{name: name, command: command, dir: dir, options: options}
+}
+
+// Name 插件的名称。
+func (p *ExecPlugin) Name() string {
+	return p.name
+}
+
+// Generate 执行插件程序，把返回的文件添加到Assets中。
+//
+// Params:
+//
+//   - b: 生成器。
+func (p *ExecPlugin) Generate(b *Builder) (*asset.Assets, error) {
+	if len(p.command) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+	req := PluginRequest{
+		Version:     PluginVersion,
+		Name:        p.name,
+		Package:     b.Package,
+		PackageName: b.PackageName,
+		Target:      b.Target,
+		Header:      b.Header,
+		Options:     p.options,
+	}
+	for _, n := range b.Nodes {
+		req.Databases = append(req.Databases, n.Database)
+	}
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	c := cmd.New(p.command...).SetStdin(bytes.NewReader(in))
+	if p.dir != "" {
+		c.SetDir(p.dir)
+	}
+	out, err := c.Run()
+	if err != nil {
+		return nil, err
+	}
+	var resp PluginResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+	assets := &asset.Assets{}
+	for _, f := range resp.Files {
+		assets.Add(f.Path, []byte(f.Content))
+	}
+	return assets, nil
+}
+
+// ServePlugin 在Go编写的插件程序的main函数中调用，从标准输入读取[PluginRequest]，
+// 根据请求创建Builder并执行插件，把生成的文件作为[PluginResponse]写入标准输出。
+//
+// Params:
+//
+//   - newPlugin: 根据项目配置文件中的options创建插件。
+//
+// Example:
+//
+//	func main() {
+//		gen.ServePlugin(func(options map[string]string) gen.Plugin {
+//			return &dtoPlugin{pkg: options["package"]}
+//		})
+//	}
+func ServePlugin(newPlugin func(options map[string]string) Plugin) {
+	resp := servePlugin(os.Stdin, newPlugin)
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+}
+
+// servePlugin 执行插件，插件的错误放在[PluginResponse]的Error中。
+//
+// Params:
+//
+//   - r: 插件请求的输入。
+//   - newPlugin: 根据options创建插件。
+func servePlugin(r io.Reader, newPlugin func(options map[string]string) Plugin) PluginResponse {
+	var req PluginRequest
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return PluginResponse{Error: fmt.Sprintf("decode request: %s", err)}
+	}
+	if req.Version != PluginVersion {
+		return PluginResponse{Error: fmt.Sprintf("unsupported plugin protocol version %q, want %q", req.Version, PluginVersion)}
+	}
+	b, err := NewBuilder(&Config{
+		Package:     req.Package,
+		PackageName: req.PackageName,
+		Target:      req.Target,
+		Header:      req.Header,
+	}, req.Databases...)
+	if err != nil {
+		return PluginResponse{Error: err.Error()}
+	}
+	assets, err := newPlugin(req.Options).Generate(b)
+	if err != nil {
+		return PluginResponse{Error: err.Error()}
+	}
+	var resp PluginResponse
+	if assets != nil {
+		for path, content := range assets.Files {
+			resp.Files = append(resp.Files, PluginFile{Path: filepath.ToSlash(path), Content: string(content)})
+		}
+	}
+	return resp
+}
+
+// runPlugins 执行全部的插件，把插件生成的文件添加到assets中。
+//
+// Params:
+//
+//   - assets: 生成的文件。
+func (t *Builder) runPlugins(assets *asset.Assets) error {
+	for _, p := range t.Plugins {
+		out, err := p.Generate(t)
+		if err != nil {
+			return fmt.Errorf("plugin %q: %w", p.Name(), err)
+		}
+		if out == nil {
+			continue
+		}
+		for dir := range out.Dirs {
+			dir = filepath.FromSlash(dir)
+			if !filepath.IsLocal(dir) {
+				return fmt.Errorf("plugin %q: directory %q is outside the target", p.Name(), dir)
+			}
+			assets.AddDir(filepath.Join(t.Target, dir))
+		}
+		for path, content := range out.Files {
+			path = filepath.FromSlash(path)
+			if !filepath.IsLocal(path) {
+				return fmt.Errorf("plugin %q: file %q is outside the target", p.Name(), path)
+			}
+			if dir := filepath.Dir(path); dir != "." {
+				assets.AddDir(filepath.Join(t.Target, dir))
+			}
+			assets.Add(filepath.Join(t.Target, path), content)
+		}
+	}
+	return nil
+}
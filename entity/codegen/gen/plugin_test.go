@@ -0,0 +1,78 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/zodileap/taurus_go/asset"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+// testPlugin 为每个数据库生成一个文件的插件。
+type testPlugin struct {
+	path string
+}
+
+func (p *testPlugin) Name() string { return "test" }
+
+func (p *testPlugin) Generate(b *Builder) (*asset.Assets, error) {
+	a := &asset.Assets{}
+	for _, n := range b.Nodes {
+		a.Add(p.path, []byte("package "+b.PackageName+" // "+n.Database.Name))
+	}
+	return a, nil
+}
+
+func TestServePlugin(t *testing.T) {
+	req, err := json.Marshal(PluginRequest{
+		Version:     PluginVersion,
+		PackageName: "entity",
+		Options:     map[string]string{"path": "dto/shop.go"},
+		Databases:   []*load.Database{{Name: "shop"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := servePlugin(bytes.NewReader(req), func(options map[string]string) Plugin {
+		return &testPlugin{path: options["path"]}
+	})
+	if resp.Error != "" {
+		t.Fatalf("执行插件失败: %s", resp.Error)
+	}
+	if len(resp.Files) != 1 || resp.Files[0].Path != "dto/shop.go" || resp.Files[0].Content != "package entity // shop" {
+		t.Fatalf("插件生成的文件不正确: %+v", resp.Files)
+	}
+
+	req, _ = json.Marshal(PluginRequest{Version: "0"})
+	if resp := servePlugin(bytes.NewReader(req), nil); resp.Error == "" {
+		t.Fatal("协议版本不一致时应返回错误")
+	}
+}
+
+func TestRunPlugins(t *testing.T) {
+	b := &Builder{Config: &Config{Target: "/project/entity", PackageName: "entity"}}
+	b.Nodes = []*DatabaseInfo{{Database: &load.Database{Name: "shop"}}}
+	b.Plugins = []Plugin{&testPlugin{path: "dto/shop.go"}}
+	var assets asset.Assets
+	if err := b.runPlugins(&assets); err != nil {
+		t.Fatalf("执行插件失败: %v", err)
+	}
+	if _, ok := assets.Files[filepath.Join("/project/entity", "dto", "shop.go")]; !ok {
+		t.Fatalf("插件生成的文件应在Target中: %v", assets.Files)
+	}
+	if _, ok := assets.Dirs[filepath.Join("/project/entity", "dto")]; !ok {
+		t.Fatalf("应添加插件生成的文件所在的目录: %v", assets.Dirs)
+	}
+
+	for _, path := range []string{"../main.go", "/tmp/main.go"} {
+		b.Plugins = []Plugin{&testPlugin{path: path}}
+		if err := b.runPlugins(&asset.Assets{}); err == nil {
+			t.Fatalf("插件生成的文件 %q 在Target之外时应返回错误", path)
+		}
+	}
+	if b.cacheable() {
+		t.Fatal("设置了插件时不应使用缓存")
+	}
+}
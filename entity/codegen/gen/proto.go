@@ -0,0 +1,160 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+	"github.com/zodileap/taurus_go/template"
+)
+
+// protoScalars Go中的类型对应的protobuf类型。
+var protoScalars = map[string]string{
+	"int8":      "int32",
+	"int16":     "int32",
+	"int32":     "int32",
+	"int64":     "int64",
+	"uint8":     "uint32",
+	"uint16":    "uint32",
+	"uint32":    "uint32",
+	"uint64":    "uint64",
+	"float32":   "float",
+	"float64":   "double",
+	"bool":      "bool",
+	"string":    "string",
+	"time.Time": "google.protobuf.Timestamp",
+}
+
+// protoGoTypes protobuf类型在protoc-gen-go生成的代码中对应的Go类型。
+var protoGoTypes = map[string]string{
+	"int32":                     "int32",
+	"int64":                     "int64",
+	"uint32":                    "uint32",
+	"uint64":                    "uint64",
+	"float":                     "float32",
+	"double":                    "float64",
+	"bool":                      "bool",
+	"string":                    "string",
+	"google.protobuf.Timestamp": "*timestamppb.Timestamp",
+}
+
+// protoPkgf 返回protobuf生成的文件路径，文件在proto/<database>目录中。
+func protoPkgf(s string) func(t template.TemplatePathFormat) string {
+	return func(t template.TemplatePathFormat) string {
+		return fmt.Sprintf(s, t.Dir(), t.Dir())
+	}
+}
+
+// protoPkg 返回数据库生成的Go package名称的前缀。
+//
+// Params:
+//
+//   - db: 数据库。
+func protoPkg(db *load.Database) string {
+	return strings.ReplaceAll(strings.ToLower(db.Name), "_", "")
+}
+
+// protoType 返回字段在protobuf中的类型，一维数组会用repeated表示，
+// 不支持的类型和多维数组返回空字符串，这些字段不会生成到protobuf中。
+//
+// Params:
+//
+//   - f: 字段。
+func protoType(f *load.Field) string {
+	typ := f.ValueType
+	if strings.HasPrefix(typ, "[]") {
+		typ = strings.TrimPrefix(typ, "[]")
+		// 数组的元素类型需要和protobuf生成的Go类型一致，才能直接赋值。
+		if t, ok := protoScalars[typ]; ok && protoGoTypes[t] == typ {
+			return "repeated " + t
+		}
+		return ""
+	}
+	return protoScalars[typ]
+}
+
+// protoGoType 返回字段在protoc-gen-go生成的代码中的Go类型，不包含repeated。
+//
+// Params:
+//
+//   - f: 字段。
+func protoGoType(f *load.Field) string {
+	return protoGoTypes[strings.TrimPrefix(protoType(f), "repeated ")]
+}
+
+// protoZero 返回字段在protoc-gen-go生成的代码中的零值。
+//
+// Params:
+//
+//   - f: 字段。
+func protoZero(f *load.Field) string {
+	switch protoGoType(f) {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "*timestamppb.Timestamp":
+		return "nil"
+	default:
+		return "0"
+	}
+}
+
+// protoGoName 返回protobuf字段在protoc-gen-go生成的代码中的名称，和protoc-gen-go的规则一致，
+// 例如"user_id"为"UserId"。
+//
+// Params:
+//
+//   - s: protobuf中的字段名称。
+func protoGoName(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case '0' <= c && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+// protoNumber 返回字段在protobuf中的序号，序号是字段在Fields()中的位置，从1开始。
+//
+// Params:
+//
+//   - i: 字段在Fields()中的索引。
+func protoNumber(i int) int {
+	return i + 1
+}
+
+// protoHasTimestamp 数据库中是否有字段使用google.protobuf.Timestamp。
+//
+// Params:
+//
+//   - db: 数据库。
+func protoHasTimestamp(db *load.Database) bool {
+	for _, e := range db.Entities {
+		for _, f := range e.Fields {
+			if protoGoType(f) == "*timestamppb.Timestamp" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isASCIILower 是否是小写字母。
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
@@ -0,0 +1,47 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+func TestProtoGoName(t *testing.T) {
+	cases := map[string]string{
+		"id":          "Id",
+		"user_id":     "UserId",
+		"created_at2": "CreatedAt2",
+		"_name":       "XName",
+		"a__b":        "A_B",
+	}
+	for in, want := range cases {
+		if got := protoGoName(in); got != want {
+			t.Fatalf("%s 的Go名称错误: %s，期望 %s", in, got, want)
+		}
+	}
+}
+
+func TestProtoType(t *testing.T) {
+	cases := map[string]string{
+		"int16":     "int32",
+		"int64":     "int64",
+		"float64":   "double",
+		"time.Time": "google.protobuf.Timestamp",
+		"[]string":  "repeated string",
+		"[]int16":   "",
+		"[][]int64": "",
+		"geo.Point": "",
+	}
+	for in, want := range cases {
+		f := &load.Field{}
+		f.ValueType = in
+		if got := protoType(f); got != want {
+			t.Fatalf("%s 的protobuf类型错误: %q，期望 %q", in, got, want)
+		}
+	}
+	f := &load.Field{}
+	f.ValueType = "time.Time"
+	if protoGoType(f) != "*timestamppb.Timestamp" || protoZero(f) != "nil" {
+		t.Fatal("时间字段的Go类型错误")
+	}
+}
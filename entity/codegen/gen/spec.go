@@ -0,0 +1,253 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/zodileap/taurus_go/asset"
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+const (
+	// SpecJSONSchema 每个entity生成一个JSON Schema文档。
+	SpecJSONSchema = "jsonschema"
+	// SpecOpenAPI 每个数据库生成一个OpenAPI 3文档，entity在components.schemas中。
+	SpecOpenAPI = "openapi"
+
+	// jsonSchemaDialect 生成的JSON Schema使用的版本，和OpenAPI 3.1一致。
+	jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// SpecFormats 可以生成的接口描述文档的格式。
+var SpecFormats = []string{SpecJSONSchema, SpecOpenAPI}
+
+// specEnabled 是否需要生成对应格式的接口描述文档。
+//
+// Params:
+//
+//   - format: 文档格式。
+func (c *Config) specEnabled(format string) bool {
+	return slices.Contains(c.Specs, format)
+}
+
+// ValidSpecFormats 检查接口描述文档的格式是否支持。
+//
+// Params:
+//
+//   - formats: 文档格式。
+func ValidSpecFormats(formats []string) error {
+	for _, f := range formats {
+		if !slices.Contains(SpecFormats, f) {
+			return fmt.Errorf("unsupported spec format %q, supported formats: %s", f, strings.Join(SpecFormats, ", "))
+		}
+	}
+	return nil
+}
+
+// genSpecs 生成数据库的接口描述文档，JSON Schema生成到spec/<database>/<entity>.schema.json，
+// OpenAPI生成到spec/<database>.openapi.json。
+//
+// Params:
+//
+//   - assets: 资源文件。
+//   - target: 目标目录。
+//   - n: 数据库节点。
+func (c *Config) genSpecs(assets *asset.Assets, target string, n *DatabaseInfo) error {
+	if c.specEnabled(SpecJSONSchema) {
+		dir := filepath.Join(target, "spec", n.Database.Name)
+		assets.AddDir(dir)
+		for _, e := range n.Database.Entities {
+			s := EntityJSONSchema(e)
+			s["$schema"] = jsonSchemaDialect
+			s["$id"] = e.AttrName + ".schema.json"
+			b, err := specMarshal(s)
+			if err != nil {
+				return fmt.Errorf("marshal json schema of %q: %w", e.Name, err)
+			}
+			assets.Add(filepath.Join(dir, e.AttrName+".schema.json"), b)
+		}
+	}
+	if c.specEnabled(SpecOpenAPI) {
+		assets.AddDir(filepath.Join(target, "spec"))
+		b, err := specMarshal(DatabaseOpenAPI(n.Database))
+		if err != nil {
+			return fmt.Errorf("marshal openapi of %q: %w", n.Database.Name, err)
+		}
+		assets.Add(filepath.Join(target, "spec", n.Database.Name+".openapi.json"), b)
+	}
+	return nil
+}
+
+// DatabaseOpenAPI 生成数据库的OpenAPI 3文档，只包含components.schemas，
+// key是entity的结构体名称。
+//
+// Params:
+//
+//   - db: 数据库。
+//
+// Returns:
+//
+//	0: OpenAPI文档。
+func DatabaseOpenAPI(db *load.Database) map[string]any {
+	schemas := map[string]any{}
+	for _, e := range db.Entities {
+		schemas[e.Name] = EntityJSONSchema(e)
+	}
+	return map[string]any{
+		"openapi":           "3.1.0",
+		"jsonSchemaDialect": jsonSchemaDialect,
+		"info": map[string]any{
+			"title":   db.Name,
+			"version": "1.0.0",
+		},
+		"paths": map[string]any{},
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
+
+// EntityJSONSchema 根据entity的字段生成JSON Schema，属性名称和entity序列化为JSON时的名称一致。
+//
+// Params:
+//
+//   - e: entity。
+//
+// Returns:
+//
+//	0: JSON Schema。
+func EntityJSONSchema(e *load.Entity) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for _, f := range e.Fields {
+		name := specPropName(f)
+		if name == "" {
+			continue
+		}
+		props[name] = FieldJSONSchema(f)
+		if f.Required {
+			required = append(required, name)
+		}
+	}
+	s := map[string]any{
+		"title":                e.Name,
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if e.Comment != "" {
+		s["description"] = e.Comment
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if e.View != nil {
+		s["readOnly"] = true
+	}
+	return s
+}
+
+// FieldJSONSchema 根据字段的类型、长度、可选值等信息生成JSON Schema。
+// 数组类型的字段会根据Depth生成嵌套的array，长度和可选值约束作用在最里层的元素上。
+//
+// Params:
+//
+//   - f: 字段。
+//
+// Returns:
+//
+//	0: JSON Schema。
+func FieldJSONSchema(f *load.Field) map[string]any {
+	s := specValueSchema(strings.TrimLeft(f.ValueType, "[]"), f)
+	for i := 0; i < strings.Count(f.ValueType, "[]"); i++ {
+		s = map[string]any{"type": "array", "items": s}
+	}
+	if typ, ok := s["type"].(string); ok && !f.Required {
+		s["type"] = []string{typ, "null"}
+	}
+	if f.Comment != "" {
+		s["description"] = f.Comment
+	}
+	if f.Locked {
+		s["readOnly"] = true
+	}
+	return s
+}
+
+// specValueSchema 根据Go中的类型生成JSON Schema，不能识别的类型返回空的Schema，表示可以是任意值。
+//
+// Params:
+//
+//   - typ: 去除数组后的Go类型。
+//   - f: 字段。
+func specValueSchema(typ string, f *load.Field) map[string]any {
+	s := map[string]any{}
+	switch typ {
+	case "int8", "int16", "int32", "uint8", "uint16":
+		s["type"] = "integer"
+		s["format"] = "int32"
+	case "int", "int64", "uint", "uint32", "uint64":
+		s["type"] = "integer"
+		s["format"] = "int64"
+	case "float32":
+		s["type"] = "number"
+		s["format"] = "float"
+	case "float64":
+		s["type"] = "number"
+		s["format"] = "double"
+	case "bool":
+		s["type"] = "boolean"
+	case "time.Time":
+		s["type"] = "string"
+		s["format"] = "date-time"
+		if strings.HasPrefix(f.AttrType, "date") {
+			s["format"] = "date"
+		}
+	case "string":
+		s["type"] = "string"
+		if strings.HasPrefix(f.AttrType, "uuid") {
+			s["format"] = "uuid"
+		}
+		if f.Size > 0 {
+			s["maxLength"] = f.Size
+		}
+		if f.MinLen > 0 {
+			s["minLength"] = f.MinLen
+		}
+		if len(f.Enums) > 0 {
+			s["enum"] = f.Enums
+		}
+	}
+	return s
+}
+
+// specPropName 返回字段序列化为JSON时的名称，优先使用Tag中的json名称，
+// 如果json名称为"-"，则返回空字符串。
+//
+// Params:
+//
+//   - f: 字段。
+func specPropName(f *load.Field) string {
+	name, _, _ := strings.Cut(reflect.StructTag(f.Tag).Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	default:
+		return name
+	}
+}
+
+// specMarshal 将文档序列化为缩进的JSON。
+func specMarshal(v any) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/codegen/load"
+)
+
+func TestValidSpecFormats(t *testing.T) {
+	if err := ValidSpecFormats([]string{"jsonschema", "openapi"}); err != nil {
+		t.Fatalf("合法的文档格式被误判: %v", err)
+	}
+	if err := ValidSpecFormats([]string{"swagger"}); err == nil {
+		t.Fatal("不支持的文档格式应返回错误")
+	}
+}
+
+func TestEntityJSONSchema(t *testing.T) {
+	id := &load.Field{}
+	id.Name, id.ValueType, id.Required, id.Primary = "ID", "int64", true, 1
+	title := &load.Field{}
+	title.Name, title.ValueType, title.Size, title.MinLen, title.Enums = "Title", "string", 20, 1, []string{"a", "b"}
+	title.Comment = "title"
+	tags := &load.Field{}
+	tags.Name, tags.ValueType, tags.Tag = "Tags", "[]string", `json:"tags,omitempty"`
+	hidden := &load.Field{}
+	hidden.Name, hidden.ValueType, hidden.Tag = "Secret", "string", `json:"-"`
+	e := &load.Entity{Name: "BlogEntity", Fields: []*load.Field{id, title, tags, hidden}}
+
+	s := EntityJSONSchema(e)
+	props := s["properties"].(map[string]any)
+	if len(props) != 3 {
+		t.Fatalf("json为\"-\"的字段不应生成属性: %v", props)
+	}
+	if req := s["required"].([]string); len(req) != 1 || req[0] != "ID" {
+		t.Fatalf("必填字段错误: %v", req)
+	}
+	if p := props["ID"].(map[string]any); p["type"] != "integer" || p["format"] != "int64" {
+		t.Fatalf("整数字段错误: %v", p)
+	}
+	p := props["Title"].(map[string]any)
+	if typ := p["type"].([]string); typ[0] != "string" || typ[1] != "null" {
+		t.Fatalf("非必填字段应可以为null: %v", p)
+	}
+	if p["maxLength"] != int64(20) || p["minLength"] != int64(1) || len(p["enum"].([]string)) != 2 || p["description"] != "title" {
+		t.Fatalf("字符串字段的约束错误: %v", p)
+	}
+	p = props["tags"].(map[string]any)
+	if items := p["items"].(map[string]any); items["type"] != "string" {
+		t.Fatalf("数组字段错误: %v", p)
+	}
+
+	doc := DatabaseOpenAPI(&load.Database{Name: "shop", Entities: map[string]*load.Entity{"blog": e}})
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	if _, ok := schemas["BlogEntity"]; !ok {
+		t.Fatalf("OpenAPI中缺少entity: %v", schemas)
+	}
+}
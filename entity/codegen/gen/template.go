@@ -49,10 +49,12 @@ var (
 		{
 			Name:   "entity/create",
 			Format: pkgf("e_%s_create.go"),
+			Skip:   skipView,
 		},
 		{
 			Name:   "entity/delete",
 			Format: pkgf("e_%s_delete.go"),
+			Skip:   skipView,
 		},
 		{
 			Name:   "entity/query",
@@ -61,6 +63,7 @@ var (
 		{
 			Name:   "entity/update",
 			Format: pkgf("e_%s_update.go"),
+			Skip:   skipView,
 		},
 		{
 			Name: "entity/meta",
@@ -81,6 +84,44 @@ var (
 			Format: pkgf("e_%s_rel.go"),
 		},
 	}
+	// DocsTemplates 数据库文档的模版，只有在Config.Docs中选择了对应的格式才会生成
+	DocsTemplates []InstanceTemplate = []InstanceTemplate{
+		{
+			Name:   "docs/markdown",
+			Format: pkgf("docs/%s.md"),
+		},
+		{
+			Name:   "docs/html",
+			Format: pkgf("docs/%s.html"),
+		},
+		{
+			Name:   "docs/mermaid",
+			Format: pkgf("docs/%s.mmd"),
+		},
+		{
+			Name:   "docs/dot",
+			Format: pkgf("docs/%s.dot"),
+		},
+	}
+	// ProtoTemplates protobuf和gRPC的模版，只有在Config.Proto为true时才会生成
+	ProtoTemplates []InstanceTemplate = []InstanceTemplate{
+		{
+			Name:   "proto/proto",
+			Format: protoPkgf("proto/%s/%s.proto"),
+		},
+		{
+			Name:   "proto/generate",
+			Format: protoPkgf("proto/%s/%s.go"),
+		},
+		{
+			Name:   "proto/convert",
+			Format: pkgf("grpc/%s/convert.go"),
+		},
+		{
+			Name:   "proto/service",
+			Format: pkgf("grpc/%s/service.go"),
+		},
+	}
 	// InstanceTemplates 内部使用的模版
 	InstanceTemplates []GenericTemplate = []GenericTemplate{
 		{
@@ -124,6 +165,8 @@ func initTemplates(builder *Builder, dbType dialect.DbDriver) {
 			ParseFS(templateDir,
 				"template/*.tmpl",
 				"template/internal/*.tmpl",
+				"template/docs/*.tmpl",
+				"template/proto/*.tmpl",
 				"template/postgresql/*.tmpl",
 				"template/postgresql/sql/*.tmpl",
 				"template/postgresql/entity/*.tmpl",
@@ -135,6 +178,8 @@ func initTemplates(builder *Builder, dbType dialect.DbDriver) {
 			ParseFS(templateDir,
 				"template/*.tmpl",
 				"template/internal/*.tmpl",
+				"template/docs/*.tmpl",
+				"template/proto/*.tmpl",
 				"template/rel/*.tmpl",
 			))
 	}
@@ -155,3 +200,13 @@ func pkgf(s string) func(t template.TemplatePathFormat) string {
 		return fmt.Sprintf(s, t.Dir())
 	}
 }
+
+// skipView 视图是只读的，不生成新增、更新和删除的代码。
+//
+// Params:
+//
+//   - info: 模版的数据。
+func skipView(info *any) bool {
+	ei, ok := (*info).(*EntityInfo)
+	return ok && ei.Entity.View != nil
+}
@@ -0,0 +1,220 @@
+package load
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zodileap/taurus_go/cmd"
+	"github.com/zodileap/taurus_go/entity"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// LoaderRun 把加载程序写入到.gen目录中，每次都通过go run执行，是默认的加载方式。
+	LoaderRun = "run"
+	// LoaderBinary 把加载程序构建为可执行文件并缓存，Schema和依赖没有变化时直接执行缓存的程序。
+	// 加载程序通过-overlay构建，不会在Schema所在的目录中写入文件，并且总是为当前机器构建，
+	// 所以可以在只读的目录和设置了GOOS、GOARCH的交叉编译环境中使用。
+	LoaderBinary = "binary"
+)
+
+// Loaders 支持的加载方式。
+var Loaders = []string{LoaderRun, LoaderBinary}
+
+// ValidLoader 检查加载方式是否支持，空字符串表示使用默认的[LoaderRun]。
+//
+// Params:
+//
+//   - loader: 加载方式。
+//
+// ErrCodes:
+//
+//   - Err_0100020025
+func ValidLoader(loader string) error {
+	if loader == "" || contains(Loaders, loader) {
+		return nil
+	}
+	return entity.Err_0100020025.Sprintf(loader)
+}
+
+// LoaderCacheDir 返回缓存加载程序的目录，在用户的缓存目录中，获取不到时使用临时目录。
+func LoaderCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "taurus_go", "entity", "loader")
+}
+
+// runBinary 执行缓存的加载程序，缓存中没有时先构建加载程序，并删除这个Schema之前缓存的程序。
+//
+// Params:
+//
+//   - pkgPath: Schema的Go package路径。
+//   - src: 加载程序的代码。
+//
+// Returns:
+//
+//	0: 加载程序的标准输出。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100020007
+//   - Err_0100020008
+func (c *Config) runBinary(pkgPath string, src []byte) (string, error) {
+	dir := LoaderCacheDir()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", entity.Err_0100020007.Sprintf(err)
+	}
+	key, err := c.binaryKey(src)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.ReplaceAll(pkgPath, "/", "_") + "-"
+	bin := filepath.Join(dir, prefix+key[:16])
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	if _, err := os.Stat(bin); err != nil {
+		if err := c.buildBinary(pkgPath, src, bin); err != nil {
+			return "", err
+		}
+		// 只保留最新的加载程序，避免缓存目录一直增长。
+		olds, _ := filepath.Glob(filepath.Join(dir, prefix+"*"))
+		for _, old := range olds {
+			if old != bin {
+				_ = os.Remove(old)
+			}
+		}
+	}
+	out, err := cmd.New(bin).Run()
+	if err != nil {
+		return "", fmt.Errorf("taurus_go/entity loader error:\n%s", err)
+	}
+	return string(out), nil
+}
+
+// buildBinary 构建加载程序。加载程序需要在Schema所在的module中构建，
+// 通过-overlay把缓存目录中的代码映射为当前目录的.gen中的文件，不需要真正写入这个文件。
+//
+// Params:
+//
+//   - pkgPath: Schema的Go package路径。
+//   - src: 加载程序的代码。
+//   - bin: 可执行文件的路径。
+//
+// ErrCodes:
+//
+//   - Err_0100020008
+func (c *Config) buildBinary(pkgPath string, src []byte, bin string) error {
+	target, err := filepath.Abs(filepath.Join(".gen", strings.ReplaceAll(pkgPath, "/", "_")+".go"))
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d", bin, os.Getpid())
+	defer os.Remove(tmp + ".go")
+	defer os.Remove(tmp + ".json")
+	if err := os.WriteFile(tmp+".go", src, 0644); err != nil {
+		return entity.Err_0100020008.Sprintf(tmp+".go", err)
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {target: tmp + ".go"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp+".json", overlay, 0644); err != nil {
+		return entity.Err_0100020008.Sprintf(tmp+".json", err)
+	}
+	flags := append([]string{"-overlay", tmp + ".json"}, c.BuildFlags...)
+	if err := cmd.GoBuild(target, tmp, flags); err != nil {
+		return err
+	}
+	// 先构建到临时文件再重命名，同时运行的生成不会执行不完整的程序。
+	return os.Rename(tmp, bin)
+}
+
+// binaryKey 计算加载程序的缓存key，包含加载程序的代码、Go的版本、构建标志，
+// 以及加载程序依赖的package：module cache中的package使用module的版本，
+// 本地的package（当前module和replace到本地目录的module）使用文件的内容。
+//
+// Params:
+//
+//   - src: 加载程序的代码。
+//
+// Returns:
+//
+//	0: 缓存key。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100020010
+//   - Err_0100020016
+func (c *Config) binaryKey(src []byte) (string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+	if err != nil {
+		return "", entity.Err_0100020016.Sprintf(err)
+	}
+	patterns := []string{c.Path}
+	for _, imp := range f.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+			patterns = append(patterns, p)
+		}
+	}
+	roots, err := packages.Load(&packages.Config{
+		BuildFlags: c.BuildFlags,
+		Env:        append(os.Environ(), "GOOS=", "GOARCH="),
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedEmbedFiles | packages.NeedModule | packages.NeedImports | packages.NeedDeps,
+	}, patterns...)
+	if err != nil {
+		return "", entity.Err_0100020010.Sprintf(err)
+	}
+	var pkgs []*packages.Package
+	packages.Visit(roots, nil, func(p *packages.Package) {
+		pkgs = append(pkgs, p)
+	})
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].PkgPath < pkgs[j].PkgPath })
+
+	h := sha256.New()
+	version, err := cmd.New("go", "env", "GOVERSION").Run()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "%s%s\n%q\n", version, os.Getenv("GOFLAGS"), c.BuildFlags)
+	h.Write(src)
+	for _, p := range pkgs {
+		// 标准库的package由Go的版本决定。
+		if p.Module == nil {
+			continue
+		}
+		m := p.Module
+		if m.Replace != nil {
+			m = m.Replace
+		}
+		if m.Version != "" {
+			fmt.Fprintf(h, "%s %s@%s\n", p.PkgPath, m.Path, m.Version)
+			continue
+		}
+		for _, name := range append(p.GoFiles, p.EmbedFiles...) {
+			b, err := os.ReadFile(name)
+			if err != nil {
+				return "", entity.Err_0100020010.Sprintf(err)
+			}
+			fmt.Fprintf(h, "%s %d\n", name, len(b))
+			h.Write(b)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
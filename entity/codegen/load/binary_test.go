@@ -0,0 +1,55 @@
+package load
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidLoader(t *testing.T) {
+	for _, loader := range []string{"", LoaderRun, LoaderBinary} {
+		if err := ValidLoader(loader); err != nil {
+			t.Fatalf("加载方式 %q 应该是有效的: %v", loader, err)
+		}
+	}
+	if err := ValidLoader("interp"); err == nil {
+		t.Fatal("不支持的加载方式应返回错误")
+	}
+}
+
+func TestLoaderCacheDir(t *testing.T) {
+	if dir := LoaderCacheDir(); !strings.HasSuffix(dir, filepath.Join("taurus_go", "entity", "loader")) {
+		t.Fatalf("加载程序的缓存目录不正确: %s", dir)
+	}
+}
+
+func TestBinaryKey(t *testing.T) {
+	src := []byte("package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+	c := &Config{Path: "github.com/zodileap/taurus_go/entity"}
+	key, err := c.binaryKey(src)
+	if err != nil {
+		t.Fatalf("binaryKey 执行失败: %v", err)
+	}
+	again, err := c.binaryKey(src)
+	if err != nil {
+		t.Fatalf("binaryKey 执行失败: %v", err)
+	}
+	if key != again {
+		t.Fatalf("相同的输入应得到相同的缓存key: %s != %s", key, again)
+	}
+	changed, err := c.binaryKey(append(src, "// changed\n"...))
+	if err != nil {
+		t.Fatalf("binaryKey 执行失败: %v", err)
+	}
+	if changed == key {
+		t.Fatal("加载程序的代码变化后缓存key应该变化")
+	}
+	c.BuildFlags = []string{"-tags=loader"}
+	flagged, err := c.binaryKey(src)
+	if err != nil {
+		t.Fatalf("binaryKey 执行失败: %v", err)
+	}
+	if flagged == key {
+		t.Fatal("构建标志变化后缓存key应该变化")
+	}
+}
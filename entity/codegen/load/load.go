@@ -35,6 +35,8 @@ type (
 		BuildFlags []string
 		// Dbs 加载的Schema中拥有匿名字段[entity.Database]的结构体的名称。
 		Dbs []DbConfig
+		// Loader 执行加载程序的方式，见[LoaderRun]和[LoaderBinary]，为空时使用[LoaderRun]。
+		Loader string
 	}
 
 	// DbConfig 是一个包含了Schema的database的信息。
@@ -94,7 +96,11 @@ var (
 //
 //   - Err_0100020004
 //   - Err_0100020005
+//   - Err_0100020025
 func (c *Config) Load() (*BuilderInfo, error) {
+	if err := ValidLoader(c.Loader); err != nil {
+		return nil, err
+	}
 	// 获取传入路径下的entity信息。
 	builder, err := c.load()
 	if err != nil {
@@ -117,17 +123,13 @@ func (c *Config) Load() (*BuilderInfo, error) {
 	if err != nil {
 		return nil, entity.Err_0100020006.Sprintf(err)
 	}
-	if err := os.MkdirAll(".gen", os.ModePerm); err != nil {
-		return nil, entity.Err_0100020007.Sprintf(err)
-	}
-	target := fmt.Sprintf(".gen/%s.go", filename(builder.PkgPath))
-	if err := os.WriteFile(target, buf, 0644); err != nil {
-		return nil, entity.Err_0100020008.Sprintf(target, err)
-	}
-	// 清理加载文件。
-	defer os.RemoveAll(".gen")
 	// 运行生成的代码，解析代码输出，得到entity。
-	out, err := cmd.GoRun(target, c.BuildFlags)
+	var out string
+	if c.Loader == LoaderBinary {
+		out, err = c.runBinary(builder.PkgPath, buf)
+	} else {
+		out, err = c.goRun(builder.PkgPath, buf)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +143,35 @@ func (c *Config) Load() (*BuilderInfo, error) {
 	return builder, nil
 }
 
+// goRun 把加载程序写入到.gen目录中，通过go run执行，执行后删除.gen目录。
+//
+// Params:
+//
+//   - pkgPath: Schema的Go package路径。
+//   - src: 加载程序的代码。
+//
+// Returns:
+//
+//	0: 加载程序的标准输出。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100020007
+//   - Err_0100020008
+func (c *Config) goRun(pkgPath string, src []byte) (string, error) {
+	if err := os.MkdirAll(".gen", os.ModePerm); err != nil {
+		return "", entity.Err_0100020007.Sprintf(err)
+	}
+	target := fmt.Sprintf(".gen/%s.go", filename(pkgPath))
+	if err := os.WriteFile(target, src, 0644); err != nil {
+		return "", entity.Err_0100020008.Sprintf(target, err)
+	}
+	// 清理加载文件。
+	defer os.RemoveAll(".gen")
+	return cmd.GoRun(target, c.BuildFlags)
+}
+
 // load 加载传入的路径中符合要求的Entity、database的信息，
 // 通过这些信息创建一个Builder。
 //
@@ -261,6 +292,8 @@ func (c *Config) load() (*BuilderInfo, error) {
 		c.Dbs = dbs
 	}
 	sort.Strings(c.Entities)
+	// 排序后生成的加载程序的代码是稳定的，见[LoaderBinary]。
+	sort.Slice(c.Dbs, func(i, j int) bool { return c.Dbs[i].Name < c.Dbs[j].Name })
 
 	// 收集Schema中额外的代码
 	var extraCodes []string
@@ -411,11 +444,11 @@ func (c *Config) cycleCause() (cause string) {
 					if ok {
 						switch x := f.Type.(type) {
 						case *ast.SelectorExpr:
-							if x.Sel.Name == "Entity" {
+							if x.Sel.Name == "Entity" || x.Sel.Name == "View" {
 								embedEntity = true
 							}
 						case *ast.Ident:
-							if name := strings.ToLower(x.Name); name == "entity" {
+							if name := strings.ToLower(x.Name); name == "entity" || name == "view" {
 								embedEntity = true
 							}
 						}
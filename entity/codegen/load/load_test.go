@@ -1,8 +1,14 @@
 package load
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/check"
+	"github.com/zodileap/taurus_go/entity/field"
 )
 
 func TestFilename(t *testing.T) {
@@ -36,3 +42,465 @@ func TestEntityTemplates(t *testing.T) {
 		t.Fatalf("导入模板定义缺失: %s", tmpls[1])
 	}
 }
+
+func TestCheckSequence(t *testing.T) {
+	if err := checkSequence(entity.NewSequence("user_id_seq"), "int64", "int8"); err != nil {
+		t.Fatalf("TSID 序列检查失败: %v", err)
+	}
+	if err := checkSequence(entity.NewSnowflakeSequence(), "int64", "int8"); err != nil {
+		t.Fatalf("Snowflake 序列检查失败: %v", err)
+	}
+	if err := checkSequence(entity.NewUUIDv7Sequence(), "string", "uuid"); err != nil {
+		t.Fatalf("UUIDv7 序列检查失败: %v", err)
+	}
+	if err := checkSequence(entity.NewSnowflakeSequence(), "string", "varchar"); err == nil {
+		t.Fatal("Snowflake 用于字符串字段时应返回错误")
+	}
+	if err := checkSequence(entity.NewULIDSequence(), "int64", "int8"); err == nil {
+		t.Fatal("ULID 用于整数字段时应返回错误")
+	}
+	if err := checkSequence(entity.NewULIDSequence(), "string", "varchar"); err != nil {
+		t.Fatalf("ULID 序列检查失败: %v", err)
+	}
+	if err := checkSequence(entity.NewULIDSequence(), "string", "uuid"); err == nil {
+		t.Fatal("ULID 用于uuid字段时应返回错误")
+	}
+	if err := checkSequence(entity.Sequence{Mode: "unknown"}, "int64", "int8"); err == nil {
+		t.Fatal("未知的序列模式应返回错误")
+	}
+}
+
+func TestFieldJSON(t *testing.T) {
+	field := Field{
+		Descriptor: entity.Descriptor{Name: "ID", Sequence: entity.NewSequence("id_seq")},
+		Validators: 2,
+	}
+	data, err := json.Marshal(field)
+	if err != nil {
+		t.Fatalf("序列化 Field 失败: %v", err)
+	}
+	var got Field
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("反序列化 Field 失败: %v", err)
+	}
+	if got.Sequence.Name == nil || *got.Sequence.Name != "id_seq" {
+		t.Fatalf("Field 的 Sequence 未保留: %s", data)
+	}
+	if got.Validators != 2 {
+		t.Fatalf("Field 的 Validators 数量不正确: %d", got.Validators)
+	}
+}
+
+func TestCheckGenerated(t *testing.T) {
+	newField := func(desc entity.Descriptor) *Field {
+		desc.EntityName, desc.AttrName = "orders", "total"
+		return &Field{Descriptor: desc}
+	}
+	if err := checkGenerated(newField(entity.Descriptor{Generated: "price * qty", Default: true})); err != nil {
+		t.Fatalf("生成列检查失败: %v", err)
+	}
+	if err := checkGenerated(newField(entity.Descriptor{Identity: true, Default: true})); err != nil {
+		t.Fatalf("标识列检查失败: %v", err)
+	}
+	cases := map[string]entity.Descriptor{
+		"同时是生成列和标识列": {Generated: "price * qty", Identity: true},
+		"标识列设置了序列":   {Identity: true, Sequence: entity.NewSequence("order_id_seq")},
+		"生成列设置了默认值":  {Generated: "price * qty", DefaultValue: "0"},
+		"标识列设置了默认值":  {Identity: true, DefaultValue: "1"},
+	}
+	for name, c := range cases {
+		if err := checkGenerated(newField(c)); err == nil {
+			t.Fatalf("%s时应返回错误", name)
+		}
+	}
+}
+
+func TestEntitySetSchema(t *testing.T) {
+	seq := entity.NewSequence("user_id_seq")
+	e := &Entity{Fields: []*Field{{Descriptor: entity.Descriptor{Sequence: seq, DefaultValue: "user_id_seq()"}}}}
+	e.SetSchema("app")
+	if e.Schema != "app" {
+		t.Fatalf("没有使用数据库默认的模式: %s", e.Schema)
+	}
+	if e.Fields[0].DefaultValue != `"app".user_id_seq()` {
+		t.Fatalf("序列默认值没有带上模式: %s", e.Fields[0].DefaultValue)
+	}
+
+	e = &Entity{Schema: "content"}
+	e.SetSchema("app")
+	if e.Schema != "content" {
+		t.Fatalf("entity指定的模式被覆盖: %s", e.Schema)
+	}
+}
+
+func TestEntityLoadTenantField(t *testing.T) {
+	e := &Entity{AttrName: "blog", Fields: []*Field{
+		{Descriptor: entity.Descriptor{Name: "TenantID", AttrName: "tenant_id", Required: true}},
+	}}
+	if err := e.loadTenantField("tenant_id"); err != nil {
+		t.Fatalf("加载租户字段失败: %v", err)
+	}
+	if e.TenantField != "TenantID" || !e.Fields[0].Default || !e.Fields[0].Locked {
+		t.Fatalf("租户字段不正确: %s %v %v", e.TenantField, e.Fields[0].Default, e.Fields[0].Locked)
+	}
+	if err := e.loadTenantField("org_id"); err == nil {
+		t.Fatal("租户字段不存在时应返回错误")
+	}
+}
+
+func TestEntityLoadIndexes(t *testing.T) {
+	age, name, email := &field.Int64{}, &field.Varchar{}, &field.Varchar{}
+	age.Init(&entity.Descriptor{Name: "Age"})
+	name.Init(&entity.Descriptor{Name: "Name"})
+	email.Init(&entity.Descriptor{Name: "Email"})
+	e := &Entity{AttrName: "user", Fields: []*Field{
+		{Descriptor: entity.Descriptor{Name: "Age", AttrName: "age"}},
+		{Descriptor: entity.Descriptor{Name: "Name", AttrName: "name"}},
+		{Descriptor: entity.Descriptor{Name: "Email", AttrName: "email"}},
+	}}
+	idx, err := e.newIndex(entity.InitIndex("").
+		Fields(age).Desc().NullsLast().Fields(name).Expr("lower(email)").
+		Include(email).Where("age > 0").Descriptor())
+	if err != nil {
+		t.Fatalf("转换索引失败: %v", err)
+	}
+	if idx.Name != "idx_user_age_name_expr" {
+		t.Fatalf("索引默认名称不正确: %s", idx.Name)
+	}
+	want := []IndexColumn{
+		{Column: "age", Desc: true, Nulls: "LAST"},
+		{Column: "name"},
+		{Column: "lower(email)", Expr: true},
+	}
+	if len(idx.Columns) != len(want) {
+		t.Fatalf("索引列数量不正确: %v", idx.Columns)
+	}
+	for i, c := range want {
+		if idx.Columns[i] != c {
+			t.Fatalf("索引第%d列不正确: %v", i, idx.Columns[i])
+		}
+	}
+	if len(idx.Include) != 1 || idx.Include[0] != "email" || idx.Where != "age > 0" {
+		t.Fatalf("索引INCLUDE或者WHERE不正确: %v %s", idx.Include, idx.Where)
+	}
+
+	other := &field.Int64{}
+	other.Init(&entity.Descriptor{Name: "Other"})
+	if _, err := e.newIndex(entity.InitIndex("idx").Fields(other).Descriptor()); err == nil {
+		t.Fatal("索引字段不存在时应返回错误")
+	}
+	if _, err := e.newIndex(entity.InitIndex("idx").Descriptor()); err == nil {
+		t.Fatal("索引没有列时应返回错误")
+	}
+}
+
+type testCheckEntity struct {
+	entity.Entity
+	checks []entity.CheckBuilder
+}
+
+func (e *testCheckEntity) Checks() []entity.CheckBuilder {
+	return e.checks
+}
+
+func TestEntityLoadChecks(t *testing.T) {
+	fields := func() []*Field {
+		return []*Field{
+			{Descriptor: entity.Descriptor{Name: "Name", AttrName: "name"}},
+			{Descriptor: entity.Descriptor{Name: "Age", AttrName: "age", CheckExpr: []byte(`{"op":">=","args":[{"op":"column","name":"age"},{"op":"value","value":0}]}`)}},
+			{Descriptor: entity.Descriptor{Name: "Email", AttrName: "email"}},
+		}
+	}
+	e := &Entity{AttrName: "users", Fields: fields()}
+	err := e.loadChecks(&testCheckEntity{checks: []entity.CheckBuilder{
+		check.New("chk_users_name", check.Length(check.Col("name")).GTE(3).Or(check.Col("name").IsNull())),
+	}})
+	if err != nil {
+		t.Fatalf("加载CHECK约束失败: %v", err)
+	}
+	if len(e.Checks) != 1 || e.Checks[0].SQL != `((length("name") >= 3) OR ("name" IS NULL))` ||
+		!reflect.DeepEqual(e.Checks[0].Columns, []string{"name"}) {
+		t.Fatalf("CHECK约束不正确: %+v", e.Checks)
+	}
+	checks := e.ExprChecks()
+	if len(checks) != 2 || checks[0].Name != "chk_users_age" || checks[1].Name != "chk_users_name" {
+		t.Fatalf("保存之前检查的约束不正确: %+v", checks)
+	}
+	if fs := e.ExprCheckFields(); len(fs) != 2 || fs[0].AttrName != "name" || fs[1].AttrName != "age" {
+		t.Fatalf("约束使用的字段不正确: %v", fs)
+	}
+
+	cases := map[string][]entity.CheckBuilder{
+		"约束名称为空":   {check.New("", check.Col("name").NotNull())},
+		"约束名称重复":   {check.New("chk_users_age", check.Col("name").NotNull())},
+		"约束的字段不存在": {check.New("chk_users_x", check.Col("x").NotNull())},
+		"约束的描述为空":  {nil},
+	}
+	for name, c := range cases {
+		if err := (&Entity{AttrName: "users", Fields: fields()}).loadChecks(&testCheckEntity{checks: c}); err == nil {
+			t.Fatalf("%s时应返回错误", name)
+		}
+	}
+	e = &Entity{AttrName: "users", Fields: fields()}
+	e.Fields[0].CheckExpr = []byte(`{"op":"<>","args":[{"op":"column","name":"name"},{"op":"column","name":"email"}]}`)
+	if err := e.checkFieldChecks(); err != nil {
+		t.Fatalf("字段的约束引用其他字段时不应返回错误: %v", err)
+	}
+	if checks := e.ExprChecks(); !reflect.DeepEqual(checks[0].Columns, []string{"email", "name"}) {
+		t.Fatalf("字段的约束使用的字段不正确: %v", checks[0].Columns)
+	}
+	if fs := e.ExprCheckFields(); len(fs) != 3 {
+		t.Fatalf("约束使用的字段不正确: %v", fs)
+	}
+	e.Fields[0].CheckExpr = []byte(`{"op":"<>","args":[{"op":"column","name":"name"},{"op":"column","name":"x"}]}`)
+	if err := e.checkFieldChecks(); err == nil {
+		t.Fatal("字段的约束使用的字段不存在时应返回错误")
+	}
+	view := &Entity{AttrName: "users", Fields: fields(), View: &View{}}
+	if err := view.loadChecks(&testCheckEntity{checks: []entity.CheckBuilder{check.New("chk", check.Col("name").NotNull())}}); err == nil {
+		t.Fatal("视图设置CHECK约束时应返回错误")
+	}
+}
+
+type testView struct {
+	entity.View
+	query string
+}
+
+func (v *testView) Config() entity.EntityConfig {
+	return entity.EntityConfig{AttrName: "user_stats"}
+}
+
+func (v *testView) ViewConfig() entity.ViewConfig {
+	return entity.ViewConfig{Query: v.query, Materialized: true}
+}
+
+func TestEntityLoadView(t *testing.T) {
+	e := &Entity{AttrName: "user_stats", Fields: []*Field{
+		{Descriptor: entity.Descriptor{Name: "UserID", AttrName: "user_id", Primary: 1}},
+		{Descriptor: entity.Descriptor{Name: "BlogCount", AttrName: "blog_count"}},
+	}}
+	if err := e.loadView(&testView{query: "SELECT user_id, count(*) AS blog_count FROM blog GROUP BY user_id;"}); err != nil {
+		t.Fatalf("加载视图失败: %v", err)
+	}
+	if e.View == nil || !e.View.Materialized || strings.HasSuffix(e.View.Query, ";") {
+		t.Fatalf("视图的定义不正确: %+v", e.View)
+	}
+	for _, f := range e.Fields {
+		if !f.Locked {
+			t.Fatalf("视图的字段 %s 应为只读", f.Name)
+		}
+	}
+	if err := (&Entity{AttrName: "user_stats", Fields: e.Fields}).loadView(&testView{}); err == nil {
+		t.Fatal("视图没有SELECT语句时应返回错误")
+	}
+	noPrimary := &Entity{AttrName: "user_stats", Fields: []*Field{
+		{Descriptor: entity.Descriptor{Name: "BlogCount", AttrName: "blog_count"}},
+	}}
+	if err := noPrimary.loadView(&testView{query: "SELECT 1 AS blog_count"}); err == nil {
+		t.Fatal("视图没有主键字段时应返回错误")
+	}
+}
+
+func TestDatabaseViews(t *testing.T) {
+	db := &Database{Entities: map[string]*Entity{
+		"User":        {AttrName: "users"},
+		"ActiveUser":  {AttrName: "users_active", View: &View{Query: `SELECT * FROM "app"."users" WHERE active`}},
+		"AdminUser":   {AttrName: "admin_users", View: &View{Query: "SELECT * FROM users_active WHERE admin"}},
+		"UserSummary": {AttrName: "user_summary", View: &View{Query: "SELECT count(*) FROM admin_users, users_active_log"}},
+	}}
+	var names []string
+	for _, v := range db.Views() {
+		names = append(names, v.AttrName)
+	}
+	if !reflect.DeepEqual(names, []string{"users_active", "admin_users", "user_summary"}) {
+		t.Fatalf("创建视图的顺序不正确: %v", names)
+	}
+	if views := db.DropViews(); views[0].AttrName != "user_summary" || views[2].AttrName != "users_active" {
+		t.Fatalf("删除视图的顺序不正确: %v", views)
+	}
+}
+
+func TestEntityCheckPartition(t *testing.T) {
+	e := &Entity{AttrName: "metric", Fields: []*Field{
+		{Descriptor: entity.Descriptor{Name: "ID", AttrName: "id", Primary: 1}},
+		{Descriptor: entity.Descriptor{Name: "Created", AttrName: "created", Primary: 2}},
+		{Descriptor: entity.Descriptor{Name: "Value", AttrName: "value"}},
+	}}
+	p := &entity.PartitionConfig{
+		Strategy:   entity.PartitionRange,
+		Fields:     []string{"created"},
+		Partitions: []entity.PartitionBound{entity.DefaultPartition("metric_default")},
+	}
+	if err := e.checkPartition(p); err != nil {
+		t.Fatalf("检查分区配置失败: %v", err)
+	}
+	cases := map[string]*entity.PartitionConfig{
+		"分区方式不支持":   {Strategy: "RANDOM", Fields: []string{"created"}},
+		"分区字段不存在":   {Strategy: entity.PartitionRange, Fields: []string{"day"}},
+		"分区字段不是主键":  {Strategy: entity.PartitionRange, Fields: []string{"value"}},
+		"列表分区有多个字段": {Strategy: entity.PartitionList, Fields: []string{"id", "created"}},
+		"分区名称重复": {Strategy: entity.PartitionHash, Fields: []string{"id"}, Partitions: []entity.PartitionBound{
+			entity.HashPartition("p", 2, 0), entity.HashPartition("p", 2, 1),
+		}},
+	}
+	for name, c := range cases {
+		if err := e.checkPartition(c); err == nil {
+			t.Fatalf("%s时应返回错误", name)
+		}
+	}
+}
+
+func TestDatabaseLoadHistory(t *testing.T) {
+	fields := func() []*Field {
+		return []*Field{
+			{Descriptor: entity.Descriptor{Name: "ID", AttrName: "id", Primary: 1}},
+			{Descriptor: entity.Descriptor{Name: "Name", AttrName: "name"}},
+		}
+	}
+	db := &Database{
+		Schema:   "app",
+		Triggers: []entity.TriggerConfig{{Name: "touch", Table: "users"}},
+		Entities: map[string]*Entity{
+			"User":  {AttrName: "users", Schema: "app", Fields: fields(), Config: entity.EntityConfig{History: true}},
+			"Blog":  {AttrName: "blog", Schema: "content", Fields: fields(), Config: entity.EntityConfig{History: true, TenantField: "name"}},
+			"Order": {AttrName: "order", Schema: "app", Fields: fields()},
+		},
+	}
+	db.loadHistory()
+	if len(db.Triggers) != 3 || db.Triggers[1].Name != "blog_history" || db.Triggers[2].Name != "users_history" {
+		t.Fatalf("历史记录的触发器不正确: %+v", db.Triggers)
+	}
+	trigger := db.Triggers[1]
+	if trigger.Schema != "content" || trigger.Table != "blog" || trigger.Timing != "AFTER" ||
+		!strings.Contains(trigger.Function, `INSERT INTO "content"."blog_history"`) ||
+		!strings.Contains(trigger.Function, `OLD."id"`) {
+		t.Fatalf("历史记录的触发器不正确: %+v", trigger)
+	}
+	if !strings.Contains(trigger.Function, `"actor", "name")`) || !strings.Contains(trigger.Function, `OLD."name" ELSE NEW."name"`) {
+		t.Fatalf("历史记录的触发器没有写入租户: %s", trigger.Function)
+	}
+	if strings.Contains(db.Triggers[2].Function, `"name"`) {
+		t.Fatalf("没有租户字段的历史记录不应写入租户: %s", db.Triggers[2].Function)
+	}
+	if schemas := db.TriggerSchemas(); !reflect.DeepEqual(schemas, []string{"app", "content"}) {
+		t.Fatalf("触发器所在的模式不正确: %v", schemas)
+	}
+	if schemas := db.EntitySchemas(); !reflect.DeepEqual(schemas, []string{"app", "content"}) {
+		t.Fatalf("实体表的模式不正确: %v", schemas)
+	}
+}
+
+func TestEntityCheckHistory(t *testing.T) {
+	e := &Entity{AttrName: "users", Fields: []*Field{{Descriptor: entity.Descriptor{Name: "Name", AttrName: "name"}}}}
+	if err := e.checkHistory(false); err != nil {
+		t.Fatalf("没有开启历史记录时不应返回错误: %v", err)
+	}
+	if err := e.checkHistory(true); err == nil {
+		t.Fatal("没有主键时应返回错误")
+	}
+	e.Fields[0].Primary = 1
+	e.View = &View{}
+	if err := e.checkHistory(true); err == nil {
+		t.Fatal("视图开启历史记录时应返回错误")
+	}
+}
+
+type testTriggerEntity struct {
+	entity.Entity
+}
+
+func (e *testTriggerEntity) Config() entity.EntityConfig {
+	return entity.EntityConfig{AttrName: "users"}
+}
+
+func TestDatabaseNewTrigger(t *testing.T) {
+	name := &field.Varchar{}
+	name.Init(&entity.Descriptor{Name: "Name"})
+	other := &field.Varchar{}
+	other.Init(&entity.Descriptor{Name: "Other"})
+	users, stats := &testTriggerEntity{}, &testView{}
+	db := &Database{
+		Name: "shop",
+		Entities: map[string]*Entity{
+			"User": {AttrName: "users", Schema: "app", Fields: []*Field{
+				{Descriptor: entity.Descriptor{Name: "Name", AttrName: "name"}},
+			}},
+			"UserStats": {AttrName: "user_stats", Schema: "app", View: &View{}},
+		},
+	}
+	trigger, err := db.newTrigger(entity.InitTrigger("user_name").On(users).Before().Insert().Update(name).
+		When("NEW.name <> 'root'").Body("RETURN NEW;").Arguments("it's").Descriptor())
+	if err != nil {
+		t.Fatalf("转换触发器失败: %v", err)
+	}
+	want := entity.TriggerConfig{
+		Name:      "user_name",
+		Schema:    "app",
+		Table:     "users",
+		Timing:    "BEFORE",
+		Event:     `INSERT OR UPDATE OF "name"`,
+		Level:     "FOR EACH ROW",
+		Function:  "RETURN NEW;",
+		Condition: "NEW.name <> ''root''",
+		Arguments: []string{"''it''''s''"},
+	}
+	if !reflect.DeepEqual(trigger, want) {
+		t.Fatalf("触发器不正确: %+v", trigger)
+	}
+
+	if _, err := db.newTrigger(entity.InitTrigger("stats").On(stats).InsteadOf().Insert().Body("RETURN NEW;").Descriptor()); err != nil {
+		t.Fatalf("视图的INSTEAD OF触发器不应返回错误: %v", err)
+	}
+	invalid := map[string]*entity.Trigger{
+		"没有实体":             entity.InitTrigger("t").After().Insert().Body("RETURN NULL;"),
+		"没有时机":             entity.InitTrigger("t").On(users).Insert().Body("RETURN NULL;"),
+		"没有事件":             entity.InitTrigger("t").On(users).After().Body("RETURN NULL;"),
+		"没有函数体":            entity.InitTrigger("t").On(users).After().Insert(),
+		"重复的事件":            entity.InitTrigger("t").On(users).After().Insert().Insert().Body("RETURN NULL;"),
+		"表上的INSTEAD OF":    entity.InitTrigger("t").On(users).InsteadOf().Insert().Body("RETURN NULL;"),
+		"语句级的INSTEAD OF":   entity.InitTrigger("t").On(stats).InsteadOf().Insert().ForEachStatement().Body("RETURN NULL;"),
+		"视图上的行级BEFORE":     entity.InitTrigger("t").On(stats).Before().Insert().Body("RETURN NULL;"),
+		"行级的TRUNCATE":      entity.InitTrigger("t").On(users).After().Truncate().Body("RETURN NULL;"),
+		"语句级的条件引用NEW":      entity.InitTrigger("t").On(users).After().Update().ForEachStatement().When("NEW.name IS NULL").Body("RETURN NULL;"),
+		"INSERT的条件引用OLD":   entity.InitTrigger("t").On(users).After().Insert().When("OLD.name IS NULL").Body("RETURN NULL;"),
+		"DELETE的条件引用NEW":   entity.InitTrigger("t").On(users).After().Delete().When("new.name IS NULL").Body("RETURN NULL;"),
+		"UPDATE OF中的字段不存在": entity.InitTrigger("t").On(users).After().Update(other).Body("RETURN NULL;"),
+	}
+	for name, b := range invalid {
+		if _, err := db.newTrigger(b.Descriptor()); err == nil {
+			t.Fatalf("%s时应返回错误", name)
+		}
+	}
+}
+
+type testTriggerDb struct {
+	entity.Database
+	triggers []entity.TriggerBuilder
+}
+
+func (d *testTriggerDb) Triggers() []entity.TriggerBuilder {
+	return d.triggers
+}
+
+func TestDatabaseLoadTriggers(t *testing.T) {
+	users := &testTriggerEntity{}
+	db := &Database{
+		Name:     "shop",
+		Triggers: []entity.TriggerConfig{{Name: "touch", Table: "users"}},
+		Entities: map[string]*Entity{"User": {AttrName: "users", Schema: "app"}},
+	}
+	di := &testTriggerDb{triggers: []entity.TriggerBuilder{
+		entity.InitTrigger("audit").On(users).After().Delete().Body("RETURN NULL;"),
+	}}
+	if err := db.loadTriggers(di); err != nil {
+		t.Fatalf("加载触发器失败: %v", err)
+	}
+	if len(db.Triggers) != 2 || db.Triggers[1].Name != "audit" || db.Triggers[1].Event != "DELETE" {
+		t.Fatalf("触发器没有添加到配置的触发器之后: %+v", db.Triggers)
+	}
+	di.triggers = []entity.TriggerBuilder{entity.InitTrigger("touch").On(users).After().Delete().Body("RETURN NULL;")}
+	if err := db.loadTriggers(di); err == nil {
+		t.Fatal("触发器的名称重复时应返回错误")
+	}
+}
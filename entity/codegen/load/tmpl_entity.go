@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/check"
 	"github.com/zodileap/taurus_go/entity/dialect"
 	stringutil "github.com/zodileap/taurus_go/stringutil"
 )
@@ -24,6 +27,8 @@ type (
 		EntityMap EntityMap
 		// Entities 数据库中的entity的信息。
 		Entities map[string]*Entity
+		// Schema 数据库中实体表默认所在的模式。
+		Schema string
 		// 添加触发器配置
 		Triggers []entity.TriggerConfig
 	}
@@ -36,6 +41,10 @@ type (
 		AttrName string `json:"attr_name,omitempty"`
 		// Comment entity的描述
 		Comment string `json:"comment,omitempty"`
+		// Schema entity的表所在的模式，为空时使用数据库默认的模式。
+		Schema string `json:"schema,omitempty"`
+		// TenantField 租户字段的名称，和字段的Name相同。
+		TenantField string `json:"tenant_field,omitempty"`
 		// Config entity配置
 		Config entity.EntityConfig `json:"config,omitempty"`
 		// Fields entity的字段
@@ -46,6 +55,63 @@ type (
 		Sequences []entity.Sequence
 		// Relations entity的关系
 		Relations []*Relation
+		// Indexes entity在Indexes()中定义的索引
+		Indexes []*Index `json:"indexes,omitempty"`
+		// Checks entity在Checks()中定义的CHECK约束
+		Checks []*Check `json:"checks,omitempty"`
+		// View entity是视图时，视图的定义
+		View *View `json:"view,omitempty"`
+	}
+
+	// View 表示视图的定义
+	View struct {
+		// Query 视图的SELECT语句
+		Query string `json:"query,omitempty"`
+		// Materialized 是否是物化视图
+		Materialized bool `json:"materialized,omitempty"`
+	}
+
+	// Index 表示entity在Indexes()中定义的索引，
+	// 不用entity.IndexDescriptor是因为其中的字段是接口类型，在序列化时会产生异常。
+	Index struct {
+		// Name 索引的名称
+		Name string `json:"name,omitempty"`
+		// Columns 索引的列
+		Columns []IndexColumn `json:"columns,omitempty"`
+		// Unique 是否是唯一索引
+		Unique bool `json:"unique,omitempty"`
+		// Method 索引的方法
+		Method string `json:"method,omitempty"`
+		// Where 部分索引的条件
+		Where string `json:"where,omitempty"`
+		// Include INCLUDE的字段在数据库中的名称
+		Include []string `json:"include,omitempty"`
+		// Concurrently 是否使用CREATE INDEX CONCURRENTLY创建索引
+		Concurrently bool `json:"concurrently,omitempty"`
+	}
+
+	// Check 表示通过check包定义的CHECK约束
+	Check struct {
+		// Name 约束的名称
+		Name string `json:"name,omitempty"`
+		// SQL 约束的条件在数据库中的语句
+		SQL string `json:"sql,omitempty"`
+		// Expr 序列化后的check.Cond，生成的代码用它在保存之前检查
+		Expr string `json:"expr,omitempty"`
+		// Columns 约束中使用的字段在数据库中的名称
+		Columns []string `json:"columns,omitempty"`
+	}
+
+	// IndexColumn 表示索引中的一列
+	IndexColumn struct {
+		// Column 字段在数据库中的名称或者表达式
+		Column string `json:"column,omitempty"`
+		// Expr Column是否是表达式
+		Expr bool `json:"expr,omitempty"`
+		// Desc 是否降序
+		Desc bool `json:"desc,omitempty"`
+		// Nulls NULL值的排序
+		Nulls string `json:"nulls,omitempty"`
 	}
 
 	// Field 表示entity的字段所包含的信息。
@@ -81,8 +147,10 @@ type (
 		Name string `json:"name,omitempty"`
 		// AttrName entity的属性名称
 		AttrName string `json:"attr_name,omitempty"`
-		Field    *Field
-		Rel      entity.Rel
+		// Schema entity的表所在的模式
+		Schema string `json:"schema,omitempty"`
+		Field  *Field
+		Rel    entity.Rel
 	}
 
 	// RelationDesc 表示entity之间的关系的描述，
@@ -178,6 +246,7 @@ func MarshalEntity(ei entity.EntityInterface) (ent *Entity, err error) {
 		// 利用反射获取entity的名称
 		AttrName: entityName,
 		Comment:  config.Comment,
+		Schema:   config.Schema,
 		Config:   config,
 	}
 	ImportPkgs = []string{}
@@ -185,6 +254,38 @@ func MarshalEntity(ei entity.EntityInterface) (ent *Entity, err error) {
 	if err := ent.loadEntity(ei); err != nil {
 		return nil, err
 	}
+	if err := ent.loadTenantField(config.TenantField); err != nil {
+		return nil, err
+	}
+	if vi, ok := ei.(entity.ViewInterface); ok {
+		if err := ent.loadView(vi); err != nil {
+			return nil, err
+		}
+	}
+	if ii, ok := ei.(interface {
+		Indexes() []entity.IndexBuilder
+	}); ok {
+		if err := ent.loadIndexes(ii); err != nil {
+			return nil, err
+		}
+	}
+	if err := ent.checkFieldChecks(); err != nil {
+		return nil, err
+	}
+	if ci, ok := ei.(interface {
+		Checks() []entity.CheckBuilder
+	}); ok {
+		if err := ent.loadChecks(ci); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ent.checkPartition(config.Partition); err != nil {
+		return nil, err
+	}
+	if err := ent.checkHistory(config.History); err != nil {
+		return nil, err
+	}
 
 	for _, f := range ent.Fields {
 		ImportPkgs = append(ImportPkgs, f.StoragerPkg)
@@ -200,6 +301,453 @@ func MarshalEntity(ei entity.EntityInterface) (ent *Entity, err error) {
 	return ent, nil
 }
 
+// loadTenantField 根据[entity.EntityConfig]中的TenantField找到租户字段。
+//
+// Params:
+//
+//   - attrName: 租户字段在数据库中的名称。
+func (e *Entity) loadTenantField(attrName string) error {
+	if attrName == "" {
+		return nil
+	}
+	for _, f := range e.Fields {
+		if f.AttrName == attrName {
+			e.TenantField = f.Name
+			// 租户字段的值在新增时从context中获取，不需要作为Create的参数，之后也不能修改。
+			f.Default = true
+			f.Locked = true
+			return nil
+		}
+	}
+	return fmt.Errorf("entity %q tenant field %q not found", e.AttrName, attrName)
+}
+
+// loadView 加载视图的定义，视图的字段都设置为只读。
+//
+// Params:
+//
+//   - vi: 视图。
+func (e *Entity) loadView(vi entity.ViewInterface) error {
+	config, err := checkViewConfig(vi)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(config.Definition())
+	if query == "" {
+		return fmt.Errorf("view %q must set the query in ViewConfig() method", e.AttrName)
+	}
+	if getPrimary(e.Fields) == nil {
+		return fmt.Errorf("view %q must set a primary field", e.AttrName)
+	}
+	for _, f := range e.Fields {
+		if f.Sequence.Name != nil {
+			return fmt.Errorf("view %q field %q can not set a sequence", e.AttrName, f.AttrName)
+		}
+		if f.Generated != "" || f.Identity {
+			return fmt.Errorf("view %q field %q can not be a generated or identity column", e.AttrName, f.AttrName)
+		}
+		if len(f.Indexes) > 0 || len(f.Uniques) > 0 {
+			return fmt.Errorf("view %q field %q can not set an index, use Indexes() on a materialized view", e.AttrName, f.AttrName)
+		}
+		f.Locked = true
+	}
+	e.View = &View{
+		Query:        strings.TrimSuffix(query, ";"),
+		Materialized: config.Materialized,
+	}
+	return nil
+}
+
+// getPrimary 获取主键字段。
+//
+// Params:
+//
+//   - fs: 字段列表。
+func getPrimary(fs []*Field) *Field {
+	for _, f := range fs {
+		if f.Primary > 0 {
+			return f
+		}
+	}
+	return nil
+}
+
+// loadIndexes 加载entity的Indexes()方法中定义的索引。
+//
+// Params:
+//
+//   - ii: 实现了Indexes()方法的entity。
+func (e *Entity) loadIndexes(ii interface {
+	Indexes() []entity.IndexBuilder
+}) error {
+	builders, err := checkIndexes(ii)
+	if err != nil {
+		return err
+	}
+	if len(builders) > 0 && e.View != nil && !e.View.Materialized {
+		return fmt.Errorf("view %q can not have indexes, only a materialized view can", e.AttrName)
+	}
+	names := map[string]bool{}
+	for _, b := range builders {
+		if b == nil || b.Descriptor() == nil {
+			return fmt.Errorf("entity %q index descriptor is nil", e.AttrName)
+		}
+		idx, err := e.newIndex(b.Descriptor())
+		if err != nil {
+			return err
+		}
+		if names[idx.Name] {
+			return fmt.Errorf("entity %q index %q already exists", e.AttrName, idx.Name)
+		}
+		names[idx.Name] = true
+		e.Indexes = append(e.Indexes, idx)
+	}
+	return nil
+}
+
+// newIndex 把[entity.IndexDescriptor]转换成可以序列化的索引，字段会转换成数据库中的名称。
+// 没有设置索引名称时，使用"idx_表名_列名"作为名称。
+//
+// Params:
+//
+//   - desc: 索引的描述。
+func (e *Entity) newIndex(desc *entity.IndexDescriptor) (*Index, error) {
+	if len(desc.Columns) == 0 {
+		return nil, fmt.Errorf("entity %q index %q has no columns", e.AttrName, desc.Name)
+	}
+	idx := &Index{
+		Name:         desc.Name,
+		Unique:       desc.Unique,
+		Method:       desc.Method,
+		Where:        desc.Where,
+		Concurrently: desc.Concurrently,
+	}
+	names := []string{e.AttrName}
+	for _, c := range desc.Columns {
+		col := IndexColumn{Desc: c.Desc, Nulls: c.Nulls}
+		switch {
+		case c.Field != nil && c.Expr != "":
+			return nil, fmt.Errorf("entity %q index %q column can not set both field and expression", e.AttrName, desc.Name)
+		case c.Field != nil:
+			f, err := e.indexField(c.Field)
+			if err != nil {
+				return nil, err
+			}
+			col.Column = f.AttrName
+			names = append(names, f.AttrName)
+		case c.Expr != "":
+			col.Column = c.Expr
+			col.Expr = true
+			names = append(names, "expr")
+		default:
+			return nil, fmt.Errorf("entity %q index %q column is empty", e.AttrName, desc.Name)
+		}
+		idx.Columns = append(idx.Columns, col)
+	}
+	for _, fb := range desc.Include {
+		f, err := e.indexField(fb)
+		if err != nil {
+			return nil, err
+		}
+		idx.Include = append(idx.Include, f.AttrName)
+	}
+	if idx.Name == "" {
+		idx.Name = "idx_" + strings.Join(names, "_")
+	}
+	return idx, nil
+}
+
+// indexField 找到索引中使用的字段。
+//
+// Params:
+//
+//   - fb: 索引中使用的字段。
+func (e *Entity) indexField(fb entity.FieldBuilder) (*Field, error) {
+	if fb == nil || fb.Descriptor() == nil {
+		return nil, fmt.Errorf("entity %q index field is nil", e.AttrName)
+	}
+	name := fb.Descriptor().Name
+	for _, f := range e.Fields {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("entity %q index field %q not found", e.AttrName, name)
+}
+
+// loadChecks 加载entity的Checks()方法中定义的CHECK约束。
+//
+// Params:
+//
+//   - ci: 实现了Checks()方法的entity。
+func (e *Entity) loadChecks(ci interface {
+	Checks() []entity.CheckBuilder
+}) error {
+	builders, err := checkChecks(ci)
+	if err != nil {
+		return err
+	}
+	if len(builders) > 0 && e.View != nil {
+		return fmt.Errorf("view %q can not have check constraints", e.AttrName)
+	}
+	names := map[string]bool{}
+	for _, f := range e.Fields {
+		if len(f.CheckExpr) > 0 {
+			names[e.fieldCheckName(f)] = true
+		}
+	}
+	for _, b := range builders {
+		if b == nil || b.Descriptor() == nil {
+			return fmt.Errorf("entity %q check descriptor is nil", e.AttrName)
+		}
+		desc := b.Descriptor()
+		if desc.Name == "" {
+			return fmt.Errorf("entity %q check constraint name is empty", e.AttrName)
+		}
+		if names[desc.Name] {
+			return fmt.Errorf("entity %q check constraint %q already exists", e.AttrName, desc.Name)
+		}
+		names[desc.Name] = true
+		c, err := e.newCheck(desc.Name, desc.Expr)
+		if err != nil {
+			return err
+		}
+		e.Checks = append(e.Checks, c)
+	}
+	return nil
+}
+
+// newCheck 解析序列化后的check.Cond，生成数据库中的语句，并检查使用的字段是否存在。
+//
+// Params:
+//
+//   - name: 约束的名称。
+//   - expr: 序列化后的check.Cond。
+func (e *Entity) newCheck(name string, expr []byte) (*Check, error) {
+	cond, err := check.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("entity %q check constraint %q: %w", e.AttrName, name, err)
+	}
+	columns := cond.Columns()
+	for _, col := range columns {
+		if !slices.ContainsFunc(e.Fields, func(f *Field) bool { return f.AttrName == col }) {
+			return nil, fmt.Errorf("entity %q check constraint %q field %q not found", e.AttrName, name, col)
+		}
+	}
+	return &Check{
+		Name:    name,
+		SQL:     cond.SQL(db.Type),
+		Expr:    string(expr),
+		Columns: columns,
+	}, nil
+}
+
+// checkFieldChecks 检查字段的CheckExpr中使用的字段是否存在，字段的约束也可以引用其他字段，
+// 所以在所有字段加载之后检查。
+func (e *Entity) checkFieldChecks() error {
+	for _, f := range e.Fields {
+		if len(f.CheckExpr) == 0 {
+			continue
+		}
+		if _, err := e.newCheck(e.fieldCheckName(f), f.CheckExpr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldCheckName 返回字段的CHECK约束的名称，和table.tmpl中的名称相同。
+//
+// Params:
+//
+//   - f: 设置了CHECK约束的字段。
+func (e *Entity) fieldCheckName(f *Field) string {
+	return "chk_" + e.AttrName + "_" + f.AttrName
+}
+
+// ExprChecks 返回字段和Checks()中通过check包定义的CHECK约束，生成的代码在保存之前检查这些约束。
+func (e *Entity) ExprChecks() []*Check {
+	var checks []*Check
+	for _, f := range e.Fields {
+		if len(f.CheckExpr) == 0 {
+			continue
+		}
+		c := &Check{
+			Name: e.fieldCheckName(f),
+			SQL:  f.CheckConstraint,
+			Expr: string(f.CheckExpr),
+		}
+		if cond, err := check.Parse(f.CheckExpr); err == nil {
+			// CheckExpr经过序列化后<和>会被转义，重新生成以便阅读生成的代码。
+			if b, err := cond.MarshalJSON(); err == nil {
+				c.Expr = string(b)
+			}
+			c.Columns = cond.Columns()
+		}
+		checks = append(checks, c)
+	}
+	return append(checks, e.Checks...)
+}
+
+// ExprCheckFields 返回ExprChecks中的约束使用的字段，按照字段的顺序排列。
+func (e *Entity) ExprCheckFields() []*Field {
+	var fs []*Field
+	checks := e.ExprChecks()
+	for _, f := range e.Fields {
+		if slices.ContainsFunc(checks, func(c *Check) bool { return slices.Contains(c.Columns, f.AttrName) }) {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+// checkPartition 检查表的分区配置，PostgreSQL要求主键和唯一约束包含所有分区键字段。
+//
+// Params:
+//
+//   - p: 分区配置。
+func (e *Entity) checkPartition(p *entity.PartitionConfig) error {
+	if p == nil {
+		return nil
+	}
+	if e.View != nil {
+		return fmt.Errorf("view %q can not be partitioned", e.AttrName)
+	}
+	switch p.Strategy {
+	case entity.PartitionRange, entity.PartitionList, entity.PartitionHash:
+	default:
+		return fmt.Errorf("entity %q unsupported partition strategy %q", e.AttrName, p.Strategy)
+	}
+	if len(p.Fields) == 0 {
+		return fmt.Errorf("entity %q partition must set fields", e.AttrName)
+	}
+	if p.Strategy == entity.PartitionList && len(p.Fields) > 1 {
+		return fmt.Errorf("entity %q list partition can only have one field", e.AttrName)
+	}
+	fields := map[string]*Field{}
+	for _, f := range e.Fields {
+		fields[f.AttrName] = f
+	}
+	for _, name := range p.Fields {
+		f, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("entity %q partition field %q not found", e.AttrName, name)
+		}
+		if f.Generated != "" {
+			return fmt.Errorf("entity %q partition field %q can not be a generated column", e.AttrName, name)
+		}
+		if getPrimary(e.Fields) != nil && f.Primary == 0 {
+			return fmt.Errorf("entity %q partition field %q must be a primary field", e.AttrName, name)
+		}
+	}
+	for _, f := range e.Fields {
+		for _, u := range f.Uniques {
+			for _, name := range p.Fields {
+				if !slices.Contains(fields[name].Uniques, u) {
+					return fmt.Errorf("entity %q unique constraint on %q must include the partition field %q", e.AttrName, f.AttrName, name)
+				}
+			}
+		}
+	}
+	for _, idx := range e.Indexes {
+		if idx.Concurrently {
+			return fmt.Errorf("entity %q index %q can not be created concurrently on a partitioned table", e.AttrName, idx.Name)
+		}
+	}
+	names := map[string]bool{}
+	for _, b := range p.Partitions {
+		if b.Name == "" {
+			return fmt.Errorf("entity %q partition name is empty", e.AttrName)
+		}
+		if names[b.Name] {
+			return fmt.Errorf("entity %q partition %q already exists", e.AttrName, b.Name)
+		}
+		names[b.Name] = true
+	}
+	return nil
+}
+
+// checkHistory 检查表的历史配置，历史表通过主键记录实体，视图不能记录历史。
+//
+// Params:
+//
+//   - history: 是否记录表的历史。
+func (e *Entity) checkHistory(history bool) error {
+	if !history {
+		return nil
+	}
+	if e.View != nil {
+		return fmt.Errorf("view %q can not enable history", e.AttrName)
+	}
+	if getPrimary(e.Fields) == nil {
+		return fmt.Errorf("entity %q must set a primary field to enable history", e.AttrName)
+	}
+	return nil
+}
+
+// historyTrigger 返回记录entity历史的触发器，触发器函数把操作类型、实体的主键、
+// 操作前后的行、[entity.HistoryActorSetting]中的操作人和租户写入历史表。
+// 触发器函数在DO语句的EXECUTE中创建，所以函数体中的单引号需要转义。
+func (e *Entity) historyTrigger() entity.TriggerConfig {
+	schema := e.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	key := getPrimary(e.Fields)
+	for _, f := range e.Fields {
+		if f.Primary == 1 {
+			key = f
+			break
+		}
+	}
+	table := e.AttrName + entity.HistoryTableSuffix
+	// 设置了租户字段时，租户也写入历史表，查询历史时按照context中的租户过滤。
+	tenantColumn, tenantValue := "", ""
+	if tenant := e.Config.TenantField; tenant != "" {
+		tenantColumn = fmt.Sprintf(`, %q`, tenant)
+		tenantValue = fmt.Sprintf(`,
+                    CASE WHEN TG_OP = ''DELETE'' THEN OLD.%[1]q ELSE NEW.%[1]q END`, tenant)
+	}
+	function := fmt.Sprintf(`INSERT INTO %[1]q.%[2]q ("operation", "entity_id", "old_row", "new_row", "actor"%[5]s)
+                VALUES (
+                    TG_OP,
+                    (CASE WHEN TG_OP = ''DELETE'' THEN OLD.%[3]q ELSE NEW.%[3]q END)::text,
+                    CASE WHEN TG_OP = ''INSERT'' THEN NULL ELSE to_jsonb(OLD) END,
+                    CASE WHEN TG_OP = ''DELETE'' THEN NULL ELSE to_jsonb(NEW) END,
+                    NULLIF(current_setting(''%[4]s'', true), '''')%[6]s
+                );
+                RETURN NULL;`, schema, table, key.AttrName, entity.HistoryActorSetting, tenantColumn, tenantValue)
+	return entity.TriggerConfig{
+		Name:     table,
+		Schema:   schema,
+		Table:    e.AttrName,
+		Timing:   "AFTER",
+		Event:    "INSERT OR UPDATE OR DELETE",
+		Level:    "FOR EACH ROW",
+		Function: function,
+	}
+}
+
+// SetSchema 设置entity的表所在的模式，entity没有在Config()中指定模式时使用数据库默认的模式。
+// 由数据库生成的序列的默认值会带上模式，避免在其他模式中找不到序列函数。
+//
+// Params:
+//
+//   - schema: 数据库默认的模式。
+func (e *Entity) SetSchema(schema string) {
+	if e.Schema == "" {
+		e.Schema = schema
+	}
+	if e.Schema == "" {
+		return
+	}
+	for _, f := range e.Fields {
+		if f.Sequence.Name != nil && !f.Sequence.IsClient() {
+			f.DefaultValue = fmt.Sprintf(`%q.%s()`, e.Schema, *f.Sequence.Name)
+		}
+	}
+}
+
 // Unmarshal 实现了[entity.EntityInterface]的entity反序列化。
 //
 // Params:
@@ -217,6 +765,270 @@ func Unmarshal(b []byte) (*Database, error) {
 	return s, nil
 }
 
+// loadHistory 为开启了历史记录的entity添加记录历史的触发器，按照entity的属性名称排序，保证生成的SQL稳定。
+func (db *Database) loadHistory() {
+	es := make([]*Entity, 0, len(db.Entities))
+	for _, e := range db.Entities {
+		if e.Config.History {
+			es = append(es, e)
+		}
+	}
+	slices.SortFunc(es, func(a, b *Entity) int {
+		return strings.Compare(a.AttrName, b.AttrName)
+	})
+	for _, e := range es {
+		db.Triggers = append(db.Triggers, e.historyTrigger())
+	}
+}
+
+// TriggerSchemas 返回触发器所在的模式，包括数据库默认的模式，
+// 生成SQL时会先删除这些模式中已经存在的触发器，再重新创建。
+//
+// Returns:
+//
+//	0: 按照名称排序的模式。
+func (db *Database) TriggerSchemas() []string {
+	schemas := []string{db.Schema}
+	if db.Schema == "" {
+		schemas[0] = "public"
+	}
+	for _, t := range db.Triggers {
+		if t.Schema != "" && !slices.Contains(schemas, t.Schema) {
+			schemas = append(schemas, t.Schema)
+		}
+	}
+	slices.Sort(schemas)
+	return schemas
+}
+
+// EntitySchemas 返回实体表指定的模式，生成SQL时每个模式只创建一次。
+//
+// Returns:
+//
+//	0: 按照名称排序的模式，不包括没有指定模式的实体表。
+func (db *Database) EntitySchemas() []string {
+	schemas := []string{}
+	for _, e := range db.Entities {
+		if e.Schema != "" && !slices.Contains(schemas, e.Schema) {
+			schemas = append(schemas, e.Schema)
+		}
+	}
+	slices.Sort(schemas)
+	return schemas
+}
+
+// Views 返回数据库中的视图，按照依赖的顺序排列，被其他视图的查询引用的视图在前面，
+// 没有依赖关系的视图按照属性名称排序。
+//
+// Returns:
+//
+//	0: 创建视图的顺序，删除时需要按照相反的顺序。
+func (db *Database) Views() []*Entity {
+	views := []*Entity{}
+	for _, e := range db.Entities {
+		if e.View != nil {
+			views = append(views, e)
+		}
+	}
+	slices.SortFunc(views, func(a, b *Entity) int {
+		return strings.Compare(a.AttrName, b.AttrName)
+	})
+	refs := make(map[*Entity]*regexp.Regexp, len(views))
+	for _, v := range views {
+		refs[v] = regexp.MustCompile(`(^|[^\w$])` + regexp.QuoteMeta(v.AttrName) + `($|[^\w$])`)
+	}
+	ordered := make([]*Entity, 0, len(views))
+	for len(views) > 0 {
+		next := 0
+		for i, v := range views {
+			if !slices.ContainsFunc(views, func(d *Entity) bool {
+				return d != v && refs[d].MatchString(v.View.Query)
+			}) {
+				next = i
+				break
+			}
+		}
+		// 有循环引用时按照名称的顺序，由数据库报告错误。
+		ordered = append(ordered, views[next])
+		views = slices.Delete(views, next, next+1)
+	}
+	return ordered
+}
+
+// DropViews 返回删除视图的顺序，和Views相反，引用其他视图的视图在前面。
+func (db *Database) DropViews() []*Entity {
+	views := db.Views()
+	slices.Reverse(views)
+	return views
+}
+
+// loadTriggers 加载数据库的Triggers()方法中定义的触发器，检查之后转换为[entity.TriggerConfig]，
+// 按照定义的顺序添加到DbConfig中的触发器之后。
+//
+// Params:
+//
+//   - di: 数据库接口，没有实现Triggers()方法时不做任何处理。
+func (db *Database) loadTriggers(di entity.DbInterface) error {
+	ti, ok := di.(interface {
+		Triggers() []entity.TriggerBuilder
+	})
+	if !ok {
+		return nil
+	}
+	builders, err := checkTriggers(ti)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, t := range db.Triggers {
+		names[t.Name] = true
+	}
+	for _, b := range builders {
+		if b == nil || b.Descriptor() == nil {
+			return fmt.Errorf("database %q trigger descriptor is nil", db.Name)
+		}
+		t, err := db.newTrigger(b.Descriptor())
+		if err != nil {
+			return err
+		}
+		if names[t.Name] {
+			return fmt.Errorf("database %q trigger %q already exists", db.Name, t.Name)
+		}
+		names[t.Name] = true
+		db.Triggers = append(db.Triggers, t)
+	}
+	return nil
+}
+
+// newTrigger 检查[entity.TriggerDescriptor]中时机、事件和级别的组合，并转换为[entity.TriggerConfig]。
+// 触发器在DO语句的EXECUTE中创建，所以函数体、条件和参数中的单引号需要转义。
+//
+// Params:
+//
+//   - desc: 触发器的描述。
+func (db *Database) newTrigger(desc *entity.TriggerDescriptor) (entity.TriggerConfig, error) {
+	var t entity.TriggerConfig
+	if desc.Name == "" {
+		return t, fmt.Errorf("database %q trigger name can not be empty", db.Name)
+	}
+	fail := func(format string, args ...any) (entity.TriggerConfig, error) {
+		return t, fmt.Errorf("trigger %q "+format, append([]any{desc.Name}, args...)...)
+	}
+	if desc.Entity == nil {
+		return fail("must set the entity with On")
+	}
+	e, err := db.extractEntity(desc.Entity)
+	if err != nil {
+		return t, err
+	}
+	view := e.View != nil
+	if view && e.View.Materialized {
+		return fail("can not be created on materialized view %q", e.AttrName)
+	}
+	switch desc.Timing {
+	case entity.TriggerBefore, entity.TriggerAfter:
+		if view && desc.Level == entity.TriggerRow {
+			return fail("on view %q must be a statement trigger, or use InsteadOf", e.AttrName)
+		}
+	case entity.TriggerInsteadOf:
+		if !view {
+			return fail("INSTEAD OF can only be used on views, %q is a table", e.AttrName)
+		}
+		if desc.Level != entity.TriggerRow {
+			return fail("INSTEAD OF must be a row trigger")
+		}
+		if desc.When != "" {
+			return fail("INSTEAD OF can not have a WHEN condition")
+		}
+		if len(desc.UpdateOf) > 0 {
+			return fail("INSTEAD OF can not set the fields of UPDATE")
+		}
+	default:
+		return fail("must set the timing with Before, After or InsteadOf")
+	}
+	switch desc.Level {
+	case entity.TriggerRow, entity.TriggerStatement:
+	default:
+		return fail("level %q is not supported", desc.Level)
+	}
+	if len(desc.Events) == 0 {
+		return fail("must set at least one event")
+	}
+	events := make([]string, 0, len(desc.Events))
+	seen := map[entity.TriggerEvent]bool{}
+	for _, ev := range desc.Events {
+		if seen[ev] {
+			return fail("event %s is set more than once", ev)
+		}
+		seen[ev] = true
+		switch ev {
+		case entity.TriggerInsert, entity.TriggerDelete:
+			events = append(events, string(ev))
+		case entity.TriggerUpdate:
+			event := string(ev)
+			if len(desc.UpdateOf) > 0 {
+				columns := make([]string, len(desc.UpdateOf))
+				for i, fb := range desc.UpdateOf {
+					if fb == nil || fb.Descriptor() == nil {
+						return fail("UPDATE field can not be nil")
+					}
+					f, err := db.extractRelField(fb, desc.Entity)
+					if err != nil {
+						return fail("UPDATE field %q is not a field of %q", fb.Descriptor().Name, e.AttrName)
+					}
+					columns[i] = fmt.Sprintf("%q", f.AttrName)
+				}
+				event += " OF " + strings.Join(columns, ", ")
+			}
+			events = append(events, event)
+		case entity.TriggerTruncate:
+			if desc.Level != entity.TriggerStatement {
+				return fail("TRUNCATE must be a statement trigger")
+			}
+			if view {
+				return fail("TRUNCATE can not be used on view %q", e.AttrName)
+			}
+			events = append(events, string(ev))
+		default:
+			return fail("event %q is not supported", ev)
+		}
+	}
+	if desc.When != "" {
+		refs := func(name string) bool {
+			return regexp.MustCompile(`(?i)\b` + name + `\b`).MatchString(desc.When)
+		}
+		switch {
+		case desc.Level == entity.TriggerStatement && (refs("NEW") || refs("OLD")):
+			return fail("statement trigger WHEN condition can not reference NEW or OLD")
+		case seen[entity.TriggerInsert] && refs("OLD"):
+			return fail("INSERT trigger WHEN condition can not reference OLD")
+		case seen[entity.TriggerDelete] && refs("NEW"):
+			return fail("DELETE trigger WHEN condition can not reference NEW")
+		}
+	}
+	if strings.TrimSpace(desc.Body) == "" {
+		return fail("must set the function body with Body")
+	}
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "'", "''")
+	}
+	t = entity.TriggerConfig{
+		Name:      desc.Name,
+		Schema:    e.Schema,
+		Table:     e.AttrName,
+		Timing:    string(desc.Timing),
+		Event:     strings.Join(events, " OR "),
+		Level:     "FOR EACH " + string(desc.Level),
+		Function:  escape(desc.Body),
+		Declare:   escape(desc.Declare),
+		Condition: escape(desc.When),
+	}
+	for _, arg := range desc.Arguments {
+		t.Arguments = append(t.Arguments, escape("'"+escape(arg)+"'"))
+	}
+	return t, nil
+}
+
 // loadRelationship 加载entity的关系。这个用于确定entity之间的关系，并在entity中添加关系。
 func (db *Database) loadRelationship(di entity.DbInterface) (err error) {
 	rels, err := checkRelationships(di)
@@ -259,6 +1071,9 @@ func (db *Database) loadRelationship(di entity.DbInterface) (err error) {
 		if err != nil {
 			return err
 		}
+		if principalEntity.View != nil || dependentEntity.View != nil {
+			return fmt.Errorf("relationship between %q and %q is not supported, views can not have relationships", principalEntity.AttrName, dependentEntity.AttrName)
+		}
 		dependentField, err = db.extractRelField(desc.ForeignKey, dependent)
 		if err != nil {
 			return err
@@ -276,12 +1091,14 @@ func (db *Database) loadRelationship(di entity.DbInterface) (err error) {
 			Principal: RelationEntity{
 				Name:     principalEntity.Name,
 				AttrName: principalEntity.AttrName,
+				Schema:   principalEntity.Schema,
 				Field:    principalField,
 				Rel:      principalRel,
 			},
 			Dependent: RelationEntity{
 				Name:     dependentEntity.Name,
 				AttrName: dependentEntity.AttrName,
+				Schema:   dependentEntity.Schema,
 				Field:    dependentField,
 				Rel:      dependentRel,
 			},
@@ -556,17 +1373,32 @@ func newField(f entity.FieldBuilder, ed *entity.Descriptor) (*Field, error) {
 	ef.Type = ed.Type
 	ef.AttrType = ed.AttrType
 	ef.Size = int64(ed.Size)
+	ef.MinLen = ed.MinLen
+	ef.Enums = ed.Enums
 	ef.Required = ed.Required
 	ef.Primary = ed.Primary
 	ef.Comment = ed.Comment
 	ef.Default = ed.Default
 	ef.DefaultValue = ed.DefaultValue
+	ef.Generated = ed.Generated
+	ef.Identity = ed.Identity
 	ef.Locked = ed.Locked
 	ef.Sequence = ed.Sequence
 	ef.Depth = ed.Depth
 	ef.BaseType = ed.BaseType
 	ef.Uniques = ed.Uniques
 	ef.CheckConstraint = ed.CheckConstraint
+	if len(ed.CheckExpr) > 0 {
+		if ef.CheckConstraint != "" {
+			return nil, fmt.Errorf("entity %q field %q can not set both Check and CheckExpr", ed.EntityName, ed.AttrName)
+		}
+		cond, err := check.Parse(ed.CheckExpr)
+		if err != nil {
+			return nil, fmt.Errorf("entity %q field %q check: %w", ed.EntityName, ed.AttrName, err)
+		}
+		ef.CheckExpr = ed.CheckExpr
+		ef.CheckConstraint = cond.SQL(db.Type)
+	}
 	ef.Indexes = ed.Indexes
 	ef.IndexName = ed.IndexName
 	ef.IndexMethod = ed.IndexMethod
@@ -576,13 +1408,33 @@ func newField(f entity.FieldBuilder, ed *entity.Descriptor) (*Field, error) {
 	ef.Templates = tmpls
 	ef.Validators = len(ed.Validators)
 
-	err := checkSequence(ef.Sequence)
+	err := checkSequence(ef.Sequence, ef.ValueType, ef.AttrType)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkGenerated(ef); err != nil {
+		return nil, err
+	}
 	return ef, nil
 }
 
+// checkGenerated 检查生成列和标识列，它们的值由数据库生成，不能同时设置默认值或者序列。
+//
+// Params:
+//
+//   - f: 字段。
+func checkGenerated(f *Field) error {
+	switch {
+	case f.Generated != "" && f.Identity:
+		return fmt.Errorf("entity %q field %q can not be both generated and identity", f.EntityName, f.AttrName)
+	case (f.Generated != "" || f.Identity) && f.Sequence.Name != nil:
+		return fmt.Errorf("entity %q field %q can not set a sequence on a generated or identity column", f.EntityName, f.AttrName)
+	case (f.Generated != "" || f.Identity) && f.DefaultValue != "":
+		return fmt.Errorf("entity %q field %q can not set a default value on a generated or identity column", f.EntityName, f.AttrName)
+	}
+	return nil
+}
+
 // analyseField 用于分析entity的字段，判断是不是Field类型，提取出里面的builder和storage来。
 //
 // Params:
@@ -699,15 +1551,79 @@ func checkFields(fd interface {
 	return fd.Fields(), nil
 }
 
+// checkViewConfig 检查视图的ViewConfig()方法是否有panic，并得到返回值。
+func checkViewConfig(vi entity.ViewInterface) (config entity.ViewConfig, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%T.ViewConfig panics: %v", vi, v)
+		}
+	}()
+	return vi.ViewConfig(), nil
+}
+
+// checkIndexes 检查entity的Indexes()方法是否有panic，并得到返回值。
+func checkIndexes(ii interface {
+	Indexes() []entity.IndexBuilder
+}) (indexes []entity.IndexBuilder, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%T.Indexes panics: %v", ii, v)
+		}
+	}()
+	return ii.Indexes(), nil
+}
+
+// checkChecks 检查entity的Checks()方法是否有panic，并得到返回值。
+func checkChecks(ci interface {
+	Checks() []entity.CheckBuilder
+}) (checks []entity.CheckBuilder, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%T.Checks panics: %v", ci, v)
+		}
+	}()
+	return ci.Checks(), nil
+}
+
+// checkTriggers 检查数据库的Triggers()方法是否有panic，并得到返回值。
+func checkTriggers(ti interface {
+	Triggers() []entity.TriggerBuilder
+}) (triggers []entity.TriggerBuilder, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%T.Triggers panics: %v", ti, v)
+		}
+	}()
+	return ti.Triggers(), nil
+}
+
 // checkSequence 检查序列的值。
 //
 // Params:
 //
 //   - seq: 序列。
-func checkSequence(seq entity.Sequence) (err error) {
+//   - valueType: 字段的值类型。
+//   - attrType: 字段在数据库中的类型，uuid类型的字段只能使用UUIDv7。
+func checkSequence(seq entity.Sequence, valueType string, attrType string) (err error) {
 	if seq.Name != nil && *seq.Name == "" {
 		return fmt.Errorf("sequence name is empty")
 	}
+	switch seq.Mode {
+	case "", entity.SequenceTSID:
+	case entity.SequenceSnowflake:
+		if valueType != "int64" {
+			return fmt.Errorf("sequence %s requires an int64 field, got %s", seq.Mode, valueType)
+		}
+	case entity.SequenceUUIDv7, entity.SequenceULID:
+		if valueType != "string" {
+			return fmt.Errorf("sequence %s requires a string field, got %s", seq.Mode, valueType)
+		}
+		if seq.Mode == entity.SequenceULID && attrType == "uuid" {
+			return fmt.Errorf("sequence %s can not be used on a uuid field, use %s instead", seq.Mode, entity.SequenceUUIDv7)
+		}
+	default:
+		return fmt.Errorf("unsupported sequence mode %s", seq.Mode)
+	}
 	return nil
 }
 
@@ -0,0 +1,103 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zodileap/taurus_go/entity/codegen/gen"
+)
+
+// ProjectConfigFile 默认的项目配置文件，在执行generate的目录中查找。
+const ProjectConfigFile = "entity.json"
+
+type (
+	// ProjectConfig 项目配置文件，例如：
+	//
+	//	{
+	//		"plugins": [
+	//			{"name": "dto", "command": ["go", "run", "./tools/dto"], "options": {"package": "dto"}}
+	//		]
+	//	}
+	ProjectConfig struct {
+		// Plugins 生成代码时执行的外部插件，按照顺序执行。
+		Plugins []PluginConfig `json:"plugins"`
+
+		// dir 配置文件所在的目录，插件的命令在这个目录中执行。
+		dir string
+	}
+
+	// PluginConfig 外部插件的配置，见[gen.ExecPlugin]。
+	PluginConfig struct {
+		// Name 插件的名称。
+		Name string `json:"name"`
+		// Command 执行插件程序的命令。
+		Command []string `json:"command"`
+		// Options 传给插件程序的options。
+		Options map[string]string `json:"options,omitempty"`
+	}
+)
+
+// LoadProjectConfig 读取项目配置文件。
+//
+// Params:
+//
+//   - path: 配置文件的路径。
+//
+// Returns:
+//
+//	0: 项目配置。
+//	1: 错误信息。
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &ProjectConfig{dir: filepath.Dir(abs)}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i, p := range c.Plugins {
+		if p.Name == "" {
+			return nil, fmt.Errorf("parse %s: plugins[%d] missing name", path, i)
+		}
+		if len(p.Command) == 0 {
+			return nil, fmt.Errorf("parse %s: plugin %q missing command", path, p.Name)
+		}
+	}
+	return c, nil
+}
+
+// Extra 把项目配置添加到代码生成的配置中。
+//
+// Returns:
+//
+//	0: Extra函数。
+func (c *ProjectConfig) Extra() Extra {
+	plugins := make([]gen.Plugin, 0, len(c.Plugins))
+	for _, p := range c.Plugins {
+		plugins = append(plugins, gen.NewExecPlugin(p.Name, p.Command, c.dir, p.Options))
+	}
+	return Plugins(plugins...)
+}
+
+// Plugins 添加代码生成的插件。
+//
+// Params:
+//
+//   - plugins: 插件。
+//
+// Returns:
+//
+//	0: Extra函数。
+func Plugins(plugins ...gen.Plugin) Extra {
+	return func(cfg *gen.Config) error {
+		cfg.Plugins = append(cfg.Plugins, plugins...)
+		return nil
+	}
+}
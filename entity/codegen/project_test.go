@@ -0,0 +1,39 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/codegen/gen"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectConfigFile)
+	content := `{"plugins": [{"name": "dto", "command": ["go", "run", "./tools/dto"], "options": {"package": "dto"}}]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("读取项目配置失败: %v", err)
+	}
+	if len(project.Plugins) != 1 || project.Plugins[0].Options["package"] != "dto" || project.dir != dir {
+		t.Fatalf("项目配置不正确: %+v", project)
+	}
+	cfg := &gen.Config{}
+	if err := project.Extra()(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Plugins) != 1 || cfg.Plugins[0].Name() != "dto" {
+		t.Fatalf("项目配置中的插件没有添加到配置中: %+v", cfg.Plugins)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"plugins": [{"name": "dto"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectConfig(path); err == nil {
+		t.Fatal("插件没有命令时应返回错误")
+	}
+}
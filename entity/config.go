@@ -9,6 +9,12 @@ type Config struct {
 	SqlConsole *bool
 	// SqlLogger sql语句的日志文件，这个是匹配tlog的日志文件名。
 	SqlLogger *string
+	// CopyThreshold Save时同一实体新增的数量达到该值，使用COPY批量写入，0表示不使用COPY。
+	// 需要读取数据库生成的值时，例如序列和默认值，先COPY到临时表再写入实体表；
+	// 某一列只有部分实体有值，或者字段的参数需要使用SQL函数格式化时，仍然使用INSERT。
+	CopyThreshold *int
+	// SnowflakeNode 生成Snowflake ID时使用的节点ID，范围是0到1023，多个实例需要设置不同的值。
+	SnowflakeNode *int64
 }
 
 var config *Config
@@ -18,10 +24,12 @@ func initConfig() {
 	batchSize := 65535
 	sqlConsole := false
 	sqlLogger := "entity"
+	copyThreshold := 1000
 	config = &Config{
-		BatchSize:  &batchSize,
-		SqlConsole: &sqlConsole,
-		SqlLogger:  &sqlLogger,
+		BatchSize:     &batchSize,
+		SqlConsole:    &sqlConsole,
+		SqlLogger:     &sqlLogger,
+		CopyThreshold: &copyThreshold,
 	}
 }
 
@@ -48,4 +56,10 @@ func SetConfig(c Config) {
 	if c.SqlLogger != nil {
 		config.SqlLogger = c.SqlLogger
 	}
+	if c.CopyThreshold != nil {
+		config.CopyThreshold = c.CopyThreshold
+	}
+	if c.SnowflakeNode != nil {
+		config.SnowflakeNode = c.SnowflakeNode
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/zodileap/taurus_go/entity/dialect"
+	dsql "github.com/zodileap/taurus_go/entity/dialect/sql"
 
 	terr "github.com/zodileap/taurus_go/err"
 )
@@ -23,6 +24,14 @@ func cloneConfig(c Config) Config {
 		value := *c.SqlLogger
 		cloned.SqlLogger = &value
 	}
+	if c.CopyThreshold != nil {
+		value := *c.CopyThreshold
+		cloned.CopyThreshold = &value
+	}
+	if c.SnowflakeNode != nil {
+		value := *c.SnowflakeNode
+		cloned.SnowflakeNode = &value
+	}
 	return cloned
 }
 
@@ -46,6 +55,7 @@ func resetConnections() {
 	mu.Lock()
 	defer mu.Unlock()
 	clients = make(map[string]ConnectionConfig)
+	drivers = make(map[string]dialect.Driver)
 }
 
 func TestConfigDefaultsAndSetConfig(t *testing.T) {
@@ -64,6 +74,9 @@ func TestConfigDefaultsAndSetConfig(t *testing.T) {
 	if current.SqlLogger == nil || *current.SqlLogger == "" {
 		t.Fatalf("默认 SqlLogger 不正确: %+v", current.SqlLogger)
 	}
+	if current.CopyThreshold == nil || *current.CopyThreshold < 0 {
+		t.Fatalf("默认 CopyThreshold 不正确: %+v", current.CopyThreshold)
+	}
 
 	newBatchSize := 128
 	SetConfig(Config{BatchSize: &newBatchSize})
@@ -78,6 +91,9 @@ func TestConfigDefaultsAndSetConfig(t *testing.T) {
 	if updated.SqlLogger == nil || *updated.SqlLogger != *original.SqlLogger {
 		t.Fatalf("SqlLogger 不应被覆盖: %+v", updated.SqlLogger)
 	}
+	if updated.CopyThreshold == nil || *updated.CopyThreshold != *original.CopyThreshold {
+		t.Fatalf("CopyThreshold 不应被覆盖: %+v", updated.CopyThreshold)
+	}
 }
 
 func TestAddConnectionValidation(t *testing.T) {
@@ -121,3 +137,24 @@ func TestGetConnectionValidation(t *testing.T) {
 	_, err = GetConnection("postgres")
 	requireEntityErrCode(t, err, Err_0100010005.Code())
 }
+
+func TestAddDriver(t *testing.T) {
+	resetConnections()
+	t.Cleanup(resetConnections)
+
+	drv := dsql.NewDriver(dialect.PostgreSQL, dsql.Conn{})
+	if err := AddDriver("fake", drv); err != nil {
+		t.Fatalf("添加驱动失败: %v", err)
+	}
+	got, err := GetConnection("fake")
+	if err != nil || got != drv {
+		t.Fatalf("应返回添加的驱动: %v %v", got, err)
+	}
+	requireEntityErrCode(t, AddDriver("fake", drv), Err_0100010004.Code())
+	requireEntityErrCode(t, AddConnection(ConnectionConfig{Tag: "fake", Driver: dialect.PostgreSQL}), Err_0100010004.Code())
+	requireEntityErrCode(t, AddDriver("", drv), Err_0100010001.Code())
+
+	RemoveConnection("fake")
+	_, err = GetConnection("fake")
+	requireEntityErrCode(t, err, Err_0100010003.Code())
+}
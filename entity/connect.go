@@ -12,6 +12,8 @@ import (
 
 var (
 	clients map[string]ConnectionConfig = make(map[string]ConnectionConfig)
+	// drivers 通过AddDriver添加的已经创建的驱动，GetConnection时直接返回。
+	drivers map[string]dialect.Driver = make(map[string]dialect.Driver)
 	mu      sync.RWMutex
 )
 
@@ -25,7 +27,7 @@ func AddConnection(conn ConnectionConfig) error {
 	}
 	switch conn.Driver {
 	case dialect.PostgreSQL, dialect.MySQL:
-		if _, ok := clients[conn.Tag]; ok {
+		if _, ok := clients[conn.Tag]; ok || drivers[conn.Tag] != nil {
 			return Err_0100010004.Sprintf(conn.Tag)
 		} else {
 			clients[conn.Tag] = conn
@@ -36,10 +38,52 @@ func AddConnection(conn ConnectionConfig) error {
 	}
 }
 
+// AddDriver 添加一个已经创建的驱动，使用tag的数据库会直接使用这个驱动，
+// 例如在测试中使用entity/dialect/fake中的驱动。
+//
+// Params:
+//
+//   - tag: 数据库的标签。
+//   - drv: 驱动。
+//
+// ErrCodes:
+//
+//   - Err_0100010001
+//   - Err_0100010004
+func AddDriver(tag string, drv dialect.Driver) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if tag == "" {
+		return Err_0100010001
+	}
+	if _, ok := clients[tag]; ok || drivers[tag] != nil {
+		return Err_0100010004.Sprintf(tag)
+	}
+	drivers[tag] = drv
+	return nil
+}
+
+// RemoveConnection 删除tag的连接配置或者通过AddDriver添加的驱动，不会关闭已经创建的连接。
+//
+// Params:
+//
+//   - tag: 数据库的标签。
+func RemoveConnection(tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(clients, tag)
+	delete(drivers, tag)
+}
+
 // GetConnection 获取一个数据库连接。
 func GetConnection(tag string) (dialect.Driver, error) {
 	mu.RLock()
 	defer mu.RUnlock()
+	if drv, ok := drivers[tag]; ok {
+		return drv, nil
+	}
 	conn, ok := clients[tag]
 	if !ok {
 		return nil, Err_0100010003.Sprintf(tag)
@@ -98,5 +142,6 @@ func GetConnection(tag string) (dialect.Driver, error) {
 		return nil, Err_010001000x.Sprintf(err)
 	}
 	drv := dsql.NewDriver(conn.Driver, dsql.Conn{ExecQuerier: db})
+	drv.SetDSN(dbUrl)
 	return drv, nil
 }
@@ -0,0 +1,248 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// DbErrorKind 数据库错误的类型。
+type DbErrorKind int
+
+const (
+	// DbErrorUnknown 没有分类的数据库错误。
+	DbErrorUnknown DbErrorKind = iota
+	// DbErrorUniqueViolation 违反唯一约束或主键约束。
+	DbErrorUniqueViolation
+	// DbErrorForeignKeyViolation 违反外键约束。
+	DbErrorForeignKeyViolation
+	// DbErrorNotNullViolation 违反非空约束。
+	DbErrorNotNullViolation
+	// DbErrorCheckViolation 违反CHECK约束。
+	DbErrorCheckViolation
+	// DbErrorSerializationFailure 可串行化事务的冲突，事务可以重试。
+	DbErrorSerializationFailure
+	// DbErrorDeadlock 检测到死锁，事务可以重试。
+	DbErrorDeadlock
+)
+
+// DbError 驱动返回的错误的分类，以及约束、表和列的名称，通过[AsDbError]获取。
+// 驱动没有返回的名称为空字符串，例如MySQL不会返回违反唯一约束的列名。
+type DbError struct {
+	// Kind 错误的类型。
+	Kind DbErrorKind
+	// Dialect 返回错误的数据库类型。
+	Dialect dialect.DbDriver
+	// Code PostgreSQL的SQLSTATE，或者MySQL的错误码。
+	Code string
+	// Schema 模式名称。
+	Schema string
+	// Table 表名。
+	Table string
+	// Column 列名。
+	Column string
+	// Constraint 约束名称。
+	Constraint string
+	// Err 驱动返回的原始错误。
+	Err error
+}
+
+// sqlStater PostgreSQL的驱动返回的错误，lib/pq和pgx的错误都实现了这个接口。
+type sqlStater interface {
+	error
+	SQLState() string
+}
+
+// pgErrorKinds PostgreSQL的SQLSTATE对应的错误类型。
+var pgErrorKinds = map[string]DbErrorKind{
+	"23505": DbErrorUniqueViolation,
+	"23503": DbErrorForeignKeyViolation,
+	"23502": DbErrorNotNullViolation,
+	"23514": DbErrorCheckViolation,
+	"40001": DbErrorSerializationFailure,
+	"40P01": DbErrorDeadlock,
+}
+
+// mysqlErrorKinds MySQL的错误码对应的错误类型。
+var mysqlErrorKinds = map[uint16]DbErrorKind{
+	1062: DbErrorUniqueViolation,
+	1451: DbErrorForeignKeyViolation,
+	1452: DbErrorForeignKeyViolation,
+	1048: DbErrorNotNullViolation,
+	1364: DbErrorNotNullViolation,
+	3819: DbErrorCheckViolation,
+	1213: DbErrorDeadlock,
+}
+
+var (
+	// pgKeyDetail PostgreSQL违反唯一约束和外键约束时Detail中的列，例如"Key (email)=(a@b.c) already exists."。
+	pgKeyDetail = regexp.MustCompile(`^Key \((.+?)\)=`)
+	// mysqlKeyMessage MySQL违反唯一约束时的约束名称，MySQL 8.0会在约束名称前加上表名。
+	mysqlKeyMessage = regexp.MustCompile(`for key '(?:([^'.]+)\.)?([^']+)'`)
+	// mysqlForeignKeyMessage MySQL违反外键约束时的表、约束和列。
+	mysqlForeignKeyMessage = regexp.MustCompile("\\(`([^`]+)`\\.`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	// mysqlColumnMessage MySQL违反非空约束时的列。
+	mysqlColumnMessage = regexp.MustCompile(`^(?:Column|Field) '([^']+)'`)
+	// mysqlCheckMessage MySQL违反CHECK约束时的约束名称。
+	mysqlCheckMessage = regexp.MustCompile(`^Check constraint '([^']+)'`)
+)
+
+// Error 返回驱动的错误信息。
+func (e *DbError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap 返回驱动返回的原始错误。
+func (e *DbError) Unwrap() error {
+	return e.Err
+}
+
+// AsDbError 把Exec、Query等返回的错误转换为[DbError]，支持PostgreSQL的驱动lib/pq返回的*pq.Error、
+// pgx返回的*pgconn.PgError，以及MySQL的驱动go-sql-driver/mysql返回的*mysql.MySQLError，
+// 错误可以被fmt.Errorf的%w包装。不是数据库的错误时返回false，是数据库的错误但没有分类时Kind为[DbErrorUnknown]。
+//
+// Params:
+//
+//   - err: 错误。
+//
+// Returns:
+//
+//	0: 数据库错误。
+//	1: 是否是数据库的错误。
+func AsDbError(err error) (*DbError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var de *DbError
+	if errors.As(err, &de) {
+		return de, true
+	}
+	var pe sqlStater
+	if errors.As(err, &pe) {
+		return pgDbError(pe), true
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if de, ok := mysqlDbError(e); ok {
+			return de, true
+		}
+	}
+	return nil, false
+}
+
+// IsUniqueViolation 错误是否是违反唯一约束或主键约束。
+func IsUniqueViolation(err error) bool {
+	return isDbError(err, DbErrorUniqueViolation)
+}
+
+// IsForeignKeyViolation 错误是否是违反外键约束。
+func IsForeignKeyViolation(err error) bool {
+	return isDbError(err, DbErrorForeignKeyViolation)
+}
+
+// IsNotNullViolation 错误是否是违反非空约束。
+func IsNotNullViolation(err error) bool {
+	return isDbError(err, DbErrorNotNullViolation)
+}
+
+// IsCheckViolation 错误是否是违反CHECK约束。
+func IsCheckViolation(err error) bool {
+	return isDbError(err, DbErrorCheckViolation)
+}
+
+// IsSerializationFailure 错误是否是可串行化事务的冲突。
+func IsSerializationFailure(err error) bool {
+	return isDbError(err, DbErrorSerializationFailure)
+}
+
+// IsDeadlock 错误是否是死锁。
+func IsDeadlock(err error) bool {
+	return isDbError(err, DbErrorDeadlock)
+}
+
+// isDbError 错误是否是指定类型的数据库错误。
+func isDbError(err error, kind DbErrorKind) bool {
+	de, ok := AsDbError(err)
+	return ok && de.Kind == kind
+}
+
+// pgDbError 转换PostgreSQL的错误，为了不依赖PostgreSQL的驱动，通过反射读取约束、表和列的名称，
+// 违反唯一约束和外键约束时PostgreSQL不会返回列名，从Detail中解析。
+func pgDbError(err sqlStater) *DbError {
+	v := reflect.Indirect(reflect.ValueOf(err))
+	de := &DbError{
+		Kind:       pgErrorKinds[err.SQLState()],
+		Dialect:    dialect.PostgreSQL,
+		Code:       err.SQLState(),
+		Schema:     errorField(v, "Schema", "SchemaName"),
+		Table:      errorField(v, "Table", "TableName"),
+		Column:     errorField(v, "Column", "ColumnName"),
+		Constraint: errorField(v, "Constraint", "ConstraintName"),
+		Err:        err,
+	}
+	if de.Column == "" {
+		if m := pgKeyDetail.FindStringSubmatch(errorField(v, "Detail")); m != nil {
+			de.Column = m[1]
+		}
+	}
+	return de
+}
+
+// errorField 读取驱动的错误结构体中第一个存在的字符串字段，lib/pq和pgx的字段名称不同。
+//
+// Params:
+//
+//   - v: 错误结构体。
+//   - names: 字段名称。
+func errorField(v reflect.Value, names ...string) string {
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, name := range names {
+		if f := v.FieldByName(name); f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
+
+// mysqlDbError 转换go-sql-driver/mysql的*mysql.MySQLError，为了不依赖MySQL的驱动，
+// 通过反射读取错误码和错误信息，约束、表和列的名称从错误信息中解析。
+func mysqlDbError(err error) (*DbError, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct || v.Elem().Type().Name() != "MySQLError" {
+		return nil, false
+	}
+	number, message := v.Elem().FieldByName("Number"), v.Elem().FieldByName("Message")
+	if number.Kind() != reflect.Uint16 || message.Kind() != reflect.String {
+		return nil, false
+	}
+	de := &DbError{
+		Kind:    mysqlErrorKinds[uint16(number.Uint())],
+		Dialect: dialect.MySQL,
+		Code:    strconv.FormatUint(number.Uint(), 10),
+		Err:     err,
+	}
+	msg := message.String()
+	switch de.Kind {
+	case DbErrorUniqueViolation:
+		if m := mysqlKeyMessage.FindStringSubmatch(msg); m != nil {
+			de.Table, de.Constraint = m[1], m[2]
+		}
+	case DbErrorForeignKeyViolation:
+		if m := mysqlForeignKeyMessage.FindStringSubmatch(msg); m != nil {
+			de.Schema, de.Table, de.Constraint, de.Column = m[1], m[2], m[3], m[4]
+		}
+	case DbErrorNotNullViolation:
+		if m := mysqlColumnMessage.FindStringSubmatch(msg); m != nil {
+			de.Column = m[1]
+		}
+	case DbErrorCheckViolation:
+		if m := mysqlCheckMessage.FindStringSubmatch(msg); m != nil {
+			de.Constraint = m[1]
+		}
+	}
+	return de, true
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// pqError 和lib/pq中的错误结构一致，测试中不能导入lib/pq，否则会注册postgres驱动。
+type pqError struct {
+	Code       string
+	Schema     string
+	Table      string
+	Column     string
+	Constraint string
+	Detail     string
+}
+
+func (e *pqError) Error() string    { return "pq: " + e.Code }
+func (e *pqError) SQLState() string { return e.Code }
+
+// PgError 和pgx中的错误结构一致。
+type PgError struct {
+	Code           string
+	TableName      string
+	ConstraintName string
+}
+
+func (e *PgError) Error() string    { return "pgx: " + e.Code }
+func (e *PgError) SQLState() string { return e.Code }
+
+// MySQLError 和go-sql-driver/mysql中的错误结构一致。
+type MySQLError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *MySQLError) Error() string { return e.Message }
+
+func TestAsDbErrorPostgreSQL(t *testing.T) {
+	err := fmt.Errorf("save: %w", &pqError{
+		Code:       "23505",
+		Table:      "users",
+		Constraint: "users_email_key",
+		Detail:     "Key (email)=(a@b.c) already exists.",
+	})
+	de, ok := AsDbError(err)
+	if !ok {
+		t.Fatal("被包装的PostgreSQL错误应该能转换为DbError")
+	}
+	if de.Kind != DbErrorUniqueViolation || de.Dialect != dialect.PostgreSQL || de.Table != "users" ||
+		de.Constraint != "users_email_key" || de.Column != "email" {
+		t.Fatalf("DbError不正确: %+v", de)
+	}
+	if !IsUniqueViolation(err) || IsForeignKeyViolation(err) {
+		t.Fatal("违反唯一约束的判断不正确")
+	}
+	var pe *pqError
+	if !errors.As(de, &pe) {
+		t.Fatal("DbError应该能获取原始的错误")
+	}
+	if de, _ := AsDbError(&PgError{Code: "23503", TableName: "blogs", ConstraintName: "blogs_user_fk"}); de.Kind != DbErrorForeignKeyViolation ||
+		de.Table != "blogs" || de.Constraint != "blogs_user_fk" {
+		t.Fatalf("pgx的错误转换不正确: %+v", de)
+	}
+
+	cases := []struct {
+		code  string
+		check func(error) bool
+	}{
+		{"23503", IsForeignKeyViolation},
+		{"23502", IsNotNullViolation},
+		{"23514", IsCheckViolation},
+		{"40001", IsSerializationFailure},
+		{"40P01", IsDeadlock},
+	}
+	for _, c := range cases {
+		if !c.check(&pqError{Code: c.code}) {
+			t.Fatalf("SQLSTATE %s 的分类不正确", c.code)
+		}
+	}
+	if de, ok := AsDbError(&pqError{Code: "42P01"}); !ok || de.Kind != DbErrorUnknown {
+		t.Fatal("没有分类的数据库错误应该为DbErrorUnknown")
+	}
+	if _, ok := AsDbError(errors.New("other")); ok {
+		t.Fatal("不是数据库的错误不应该转换为DbError")
+	}
+}
+
+func TestAsDbErrorMySQL(t *testing.T) {
+	cases := []struct {
+		err  *MySQLError
+		want DbError
+	}{
+		{
+			&MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.users_email_key'"},
+			DbError{Kind: DbErrorUniqueViolation, Code: "1062", Table: "users", Constraint: "users_email_key"},
+		},
+		{
+			&MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`shop`.`blogs`, CONSTRAINT `blogs_user_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"},
+			DbError{Kind: DbErrorForeignKeyViolation, Code: "1452", Schema: "shop", Table: "blogs", Constraint: "blogs_user_fk", Column: "user_id"},
+		},
+		{
+			&MySQLError{Number: 1048, Message: "Column 'name' cannot be null"},
+			DbError{Kind: DbErrorNotNullViolation, Code: "1048", Column: "name"},
+		},
+		{
+			&MySQLError{Number: 3819, Message: "Check constraint 'users_age_check' is violated."},
+			DbError{Kind: DbErrorCheckViolation, Code: "3819", Constraint: "users_age_check"},
+		},
+		{
+			&MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"},
+			DbError{Kind: DbErrorDeadlock, Code: "1213"},
+		},
+	}
+	for _, c := range cases {
+		de, ok := AsDbError(fmt.Errorf("exec: %w", c.err))
+		if !ok {
+			t.Fatalf("MySQL的错误 %d 应该能转换为DbError", c.err.Number)
+		}
+		c.want.Dialect, c.want.Err = dialect.MySQL, c.err
+		if *de != c.want {
+			t.Fatalf("MySQL的错误 %d 转换不正确: %+v", c.err.Number, de)
+		}
+	}
+}
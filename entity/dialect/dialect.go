@@ -53,6 +53,13 @@ type Tx interface {
 	Dialect() DbDriver
 }
 
+// Copier 支持使用COPY批量写入数据的事务，目前只有PostgreSQL支持。
+type Copier interface {
+	// CopyFrom 使用COPY ... FROM STDIN 把rows逐行写入到table的columns中。
+	// schema为空时使用数据库默认的schema，返回写入的行数。
+	CopyFrom(ctx context.Context, schema string, table string, columns []string, rows [][]any) (int64, error)
+}
+
 // RowScanner 封装了sql.Row的标准方法，用于扫描数据库行。
 type RowsScanner interface {
 	Close() error
@@ -63,3 +70,28 @@ type RowsScanner interface {
 	NextResultSet() bool
 	Scan(dest ...any) error
 }
+
+// Notification PostgreSQL通过NOTIFY发送的通知。
+type Notification struct {
+	// Channel 通知的频道。
+	Channel string
+	// Payload 通知的内容。
+	Payload string
+}
+
+// Listener 通过LISTEN接收通知的连接，连接断开时会自动重新连接并重新LISTEN。
+type Listener interface {
+	// Listen 开始监听频道，连接还没有建立时会等待连接建立。
+	Listen(ctx context.Context, channel string) error
+	// Notify 返回接收通知的通道，连接重新建立后会收到一个nil，表示断开期间的通知可能已经丢失，
+	// Close之后通道会被关闭。
+	Notify() <-chan *Notification
+	// Close 关闭连接。
+	Close() error
+}
+
+// Notifier 可以创建[Listener]的驱动。
+type Notifier interface {
+	// Listener 创建一个新的监听连接。
+	Listener(ctx context.Context) (Listener, error)
+}
@@ -0,0 +1,498 @@
+// Package fake 提供一个内存中的[dialect.Driver]，用于在没有数据库的情况下测试生成的代码。
+// 通过ExpectExec、ExpectQuery设置预期的SQL、参数和返回的结果，
+// 执行后可以检查记录的调用、事务的状态，以及所有的预期是否都被执行。
+// 驱动实现了[dialect.Notifier]，通过Notify和Reconnect模拟NOTIFY和监听连接的重新连接。
+//
+// Example:
+//
+//	drv := fake.New(dialect.PostgreSQL)
+//	drv.ExpectQuery(`SELECT "id", "name" FROM "users" WHERE "id" = $1`).
+//		WithArgs(int64(1)).
+//		WillReturnRows(fake.NewRows("id", "name").AddRow(int64(1), "taurus"))
+//	entity.AddDriver("shop", drv)
+//	defer entity.RemoveConnection("shop")
+//	// 执行使用生成的数据库的代码
+//	if err := drv.ExpectationsWereMet(); err != nil {
+//		t.Fatal(err)
+//	}
+package fake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// AnyArg 匹配任意值的参数，例如生成的ID或者时间。
+var AnyArg = anyArg{}
+
+// 调用的类型。
+const (
+	// KindExec Exec调用。
+	KindExec = "exec"
+	// KindQuery Query调用。
+	KindQuery = "query"
+)
+
+// 事务的状态。
+const (
+	// TxOpen 事务还没有提交或回滚。
+	TxOpen TxState = iota
+	// TxCommitted 事务已经提交。
+	TxCommitted
+	// TxRolledBack 事务已经回滚。
+	TxRolledBack
+)
+
+type (
+	// Driver 内存中的数据库驱动，实现了[dialect.Driver]，可以在多个goroutine中使用。
+	Driver struct {
+		mu      sync.Mutex
+		dialect dialect.DbDriver
+		expects []*Expectation
+		calls   []Call
+		// unexpected 没有匹配预期的调用。
+		unexpected []string
+		txs        []*Tx
+		beginErr   error
+		closed     bool
+		// listeners 通过Listener创建的监听连接，Notify和Reconnect会发送给这些连接。
+		listeners []*Listener
+	}
+
+	// Tx 内存中的事务，实现了[dialect.Tx]，Exec和Query使用Driver中的预期。
+	Tx struct {
+		drv   *Driver
+		state TxState
+		err   error
+	}
+
+	// TxState 事务的状态。
+	TxState int
+
+	// Call 记录的一次Exec或Query调用。
+	Call struct {
+		// Kind 调用的类型，[KindExec]或[KindQuery]。
+		Kind string
+		// Query 执行的SQL。
+		Query string
+		// Args SQL的参数。
+		Args []any
+		// Tx 执行调用的事务，不在事务中时为nil。
+		Tx *Tx
+		// Err 返回的错误。
+		Err error
+	}
+
+	// Expectation 一次预期的Exec或Query调用，每个预期只会匹配一次调用。
+	Expectation struct {
+		kind     string
+		query    string
+		re       *regexp.Regexp
+		args     []any
+		hasArgs  bool
+		rows     *Rows
+		result   sql.Result
+		err      error
+		consumed bool
+	}
+
+	// Result 实现了sql.Result。
+	Result struct {
+		// LastID LastInsertId返回的值。
+		LastID int64
+		// Affected RowsAffected返回的值。
+		Affected int64
+	}
+
+	anyArg struct{}
+)
+
+// New 创建一个内存中的数据库驱动。
+//
+// Params:
+//
+//   - d: Dialect返回的数据库类型，生成的代码会根据数据库类型生成不同的SQL。
+//
+// Returns:
+//
+//	0: 数据库驱动。
+func New(d dialect.DbDriver) *Driver {
+	return &Driver{dialect: d}
+}
+
+// ExpectExec 添加一个预期的Exec调用，SQL中连续的空白字符会被视为一个空格。
+//
+// Params:
+//
+//   - query: 预期的SQL。
+//
+// Returns:
+//
+//	0: 预期，用于设置参数和返回值。
+func (d *Driver) ExpectExec(query string) *Expectation {
+	return d.expect(KindExec, query)
+}
+
+// ExpectQuery 添加一个预期的Query调用，SQL中连续的空白字符会被视为一个空格。
+//
+// Params:
+//
+//   - query: 预期的SQL。
+//
+// Returns:
+//
+//	0: 预期，用于设置参数和返回值。
+func (d *Driver) ExpectQuery(query string) *Expectation {
+	return d.expect(KindQuery, query)
+}
+
+// WillFailBegin 设置Tx返回的错误。
+//
+// Params:
+//
+//   - err: 错误。
+func (d *Driver) WillFailBegin(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.beginErr = err
+}
+
+// Calls 返回记录的全部调用，包含没有匹配预期的调用。
+func (d *Driver) Calls() []Call {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Call(nil), d.calls...)
+}
+
+// Txs 返回通过Tx创建的全部事务。
+func (d *Driver) Txs() []*Tx {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*Tx(nil), d.txs...)
+}
+
+// Closed 是否已经调用了Close。
+func (d *Driver) Closed() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+// ExpectationsWereMet 检查全部的预期是否都被执行，以及是否有没有匹配预期的调用。
+//
+// Returns:
+//
+//	0: 没有执行的预期和没有匹配的调用，都满足时返回nil。
+func (d *Driver) ExpectationsWereMet() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var msgs []string
+	for _, e := range d.expects {
+		if !e.consumed {
+			msgs = append(msgs, fmt.Sprintf("expected %s was not called: %s", e.kind, e))
+		}
+	}
+	msgs = append(msgs, d.unexpected...)
+	if len(msgs) > 0 {
+		return fmt.Errorf("fake: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// Exec 实现了[dialect.ExecQuerier]。
+func (d *Driver) Exec(ctx context.Context, query string, args []any, v any) error {
+	return d.exec(nil, query, args, v)
+}
+
+// Query 实现了[dialect.ExecQuerier]。
+func (d *Driver) Query(ctx context.Context, query string, args []any, v *dialect.Rows) error {
+	return d.query(nil, query, args, v)
+}
+
+// Tx 开始一个事务。
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.beginErr != nil {
+		return nil, d.beginErr
+	}
+	tx := &Tx{drv: d}
+	d.txs = append(d.txs, tx)
+	return tx, nil
+}
+
+// Close 关闭驱动，只会记录状态。
+func (d *Driver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+// Dialect 返回数据库类型。
+func (d *Driver) Dialect() dialect.DbDriver {
+	return d.dialect
+}
+
+// expect 添加一个预期。
+func (d *Driver) expect(kind string, query string) *Expectation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := &Expectation{kind: kind, query: normalize(query)}
+	d.expects = append(d.expects, e)
+	return e
+}
+
+// match 找到第一个没有执行并且匹配的预期，记录调用。
+func (d *Driver) match(kind string, tx *Tx, query string, args []any) (*Expectation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	call := Call{Kind: kind, Query: query, Args: args, Tx: tx}
+	if tx != nil && tx.state != TxOpen {
+		call.Err = fmt.Errorf("fake: %s in a finished transaction: %s", kind, query)
+		d.calls = append(d.calls, call)
+		return nil, call.Err
+	}
+	for _, e := range d.expects {
+		if !e.consumed && e.matches(kind, query, args) {
+			e.consumed = true
+			call.Err = e.err
+			d.calls = append(d.calls, call)
+			return e, nil
+		}
+	}
+	call.Err = fmt.Errorf("fake: unexpected %s: %s %v", kind, query, args)
+	d.calls = append(d.calls, call)
+	d.unexpected = append(d.unexpected, fmt.Sprintf("unexpected %s: %s %v", kind, query, args))
+	return nil, call.Err
+}
+
+// exec 执行Exec，把预期的结果写入v。
+func (d *Driver) exec(tx *Tx, query string, args []any, v any) error {
+	e, err := d.match(KindExec, tx, query, args)
+	if err != nil {
+		return err
+	}
+	if e.err != nil {
+		return e.err
+	}
+	switch v := v.(type) {
+	case nil:
+	case *sql.Result:
+		if e.result != nil {
+			*v = e.result
+		} else {
+			*v = Result{}
+		}
+	default:
+		return fmt.Errorf("fake: invalid type %T. expect *sql.Result", v)
+	}
+	return nil
+}
+
+// query 执行Query，把预期的行写入v，没有设置行时返回空的结果。
+func (d *Driver) query(tx *Tx, query string, args []any, v *dialect.Rows) error {
+	e, err := d.match(KindQuery, tx, query, args)
+	if err != nil {
+		return err
+	}
+	if e.err != nil {
+		return e.err
+	}
+	rows := e.rows
+	if rows == nil {
+		rows = NewRows()
+	}
+	v.RowsScanner = rows.clone()
+	return nil
+}
+
+// WithArgs 设置预期的参数，实现了driver.Valuer的参数会先转换为值再比较，[AnyArg]匹配任意值。
+//
+// Params:
+//
+//   - args: 预期的参数。
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args, e.hasArgs = args, true
+	return e
+}
+
+// Regexp 把预期的SQL作为正则表达式匹配。
+func (e *Expectation) Regexp() *Expectation {
+	e.re = regexp.MustCompile(e.query)
+	return e
+}
+
+// WillReturnRows 设置Query返回的行。
+//
+// Params:
+//
+//   - rows: 返回的行。
+func (e *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	e.rows = rows
+	return e
+}
+
+// WillReturnResult 设置Exec返回的结果。
+//
+// Params:
+//
+//   - lastID: LastInsertId返回的值。
+//   - affected: RowsAffected返回的值。
+func (e *Expectation) WillReturnResult(lastID int64, affected int64) *Expectation {
+	e.result = Result{LastID: lastID, Affected: affected}
+	return e
+}
+
+// WillReturnError 设置调用返回的错误，例如数据库的约束错误。
+//
+// Params:
+//
+//   - err: 错误。
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// String 返回预期的SQL和参数。
+func (e *Expectation) String() string {
+	if e.hasArgs {
+		return fmt.Sprintf("%s %v", e.query, e.args)
+	}
+	return e.query
+}
+
+// matches 调用是否匹配预期。
+func (e *Expectation) matches(kind string, query string, args []any) bool {
+	if e.kind != kind {
+		return false
+	}
+	query = normalize(query)
+	if e.re != nil {
+		if !e.re.MatchString(query) {
+			return false
+		}
+	} else if e.query != query {
+		return false
+	}
+	if !e.hasArgs {
+		return true
+	}
+	if len(e.args) != len(args) {
+		return false
+	}
+	for i := range args {
+		if _, ok := e.args[i].(anyArg); ok {
+			continue
+		}
+		if !reflect.DeepEqual(argValue(e.args[i]), argValue(args[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Exec 实现了[dialect.ExecQuerier]。
+func (t *Tx) Exec(ctx context.Context, query string, args []any, v any) error {
+	return t.drv.exec(t, query, args, v)
+}
+
+// Query 实现了[dialect.ExecQuerier]。
+func (t *Tx) Query(ctx context.Context, query string, args []any, v *dialect.Rows) error {
+	return t.drv.query(t, query, args, v)
+}
+
+// Commit 提交事务，事务已经结束时返回错误。
+func (t *Tx) Commit() error {
+	return t.finish(TxCommitted)
+}
+
+// Rollback 回滚事务，事务已经结束时返回错误。
+func (t *Tx) Rollback() error {
+	return t.finish(TxRolledBack)
+}
+
+// Dialect 返回数据库类型。
+func (t *Tx) Dialect() dialect.DbDriver {
+	return t.drv.dialect
+}
+
+// State 返回事务的状态。
+func (t *Tx) State() TxState {
+	t.drv.mu.Lock()
+	defer t.drv.mu.Unlock()
+	return t.state
+}
+
+// WillFailCommit 设置Commit返回的错误，Commit失败时事务的状态不会改变。
+//
+// Params:
+//
+//   - err: 错误。
+func (t *Tx) WillFailCommit(err error) {
+	t.drv.mu.Lock()
+	defer t.drv.mu.Unlock()
+	t.err = err
+}
+
+// finish 结束事务。
+func (t *Tx) finish(state TxState) error {
+	t.drv.mu.Lock()
+	defer t.drv.mu.Unlock()
+	if t.state != TxOpen {
+		return fmt.Errorf("fake: transaction has already been %s", t.state)
+	}
+	if state == TxCommitted && t.err != nil {
+		return t.err
+	}
+	t.state = state
+	return nil
+}
+
+// String 返回事务状态的名称。
+func (s TxState) String() string {
+	switch s {
+	case TxCommitted:
+		return "committed"
+	case TxRolledBack:
+		return "rolled back"
+	default:
+		return "open"
+	}
+}
+
+// LastInsertId 实现了sql.Result。
+func (r Result) LastInsertId() (int64, error) {
+	return r.LastID, nil
+}
+
+// RowsAffected 实现了sql.Result。
+func (r Result) RowsAffected() (int64, error) {
+	return r.Affected, nil
+}
+
+// normalize 把SQL中连续的空白字符替换为一个空格。
+func normalize(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// argValue 把实现了driver.Valuer的参数转换为值。
+func argValue(arg any) any {
+	if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+	if v, ok := arg.(driver.Valuer); ok {
+		if value, err := v.Value(); err == nil {
+			return value
+		}
+	}
+	return arg
+}
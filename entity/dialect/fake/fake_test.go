@@ -0,0 +1,153 @@
+package fake
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/entitysql"
+)
+
+// nullName 实现了sql.Scanner和driver.Valuer。
+type nullName struct {
+	sql.NullString
+}
+
+func TestDriverQuery(t *testing.T) {
+	drv := New(dialect.PostgreSQL)
+	drv.ExpectQuery(`SELECT "id", "name"
+		FROM "users" WHERE "id" = $1`).
+		WithArgs(int64(1)).
+		WillReturnRows(NewRows("id", "name").AddRow(int64(1), "taurus").AddRow(int32(2), nil))
+
+	var rows dialect.Rows
+	if err := drv.Query(context.Background(), `SELECT "id", "name" FROM "users" WHERE "id" = $1`, []any{int64(1)}, &rows); err != nil {
+		t.Fatalf("Query失败: %v", err)
+	}
+	var (
+		ids   []int64
+		names []nullName
+	)
+	for rows.Next() {
+		var (
+			id   int64
+			name nullName
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("Scan失败: %v", err)
+		}
+		ids, names = append(ids, id), append(names, name)
+	}
+	if len(ids) != 2 || ids[1] != 2 || names[0].String != "taurus" || names[1].Valid {
+		t.Fatalf("扫描的结果不正确: %v %v", ids, names)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatalf("预期应该全部被执行: %v", err)
+	}
+}
+
+func TestDriverArgsAndUnexpected(t *testing.T) {
+	drv := New(dialect.PostgreSQL)
+	drv.ExpectExec(`DELETE FROM "users" WHERE "id" = $1 AND "name" = $2`).
+		WithArgs(AnyArg, "taurus").
+		WillReturnResult(0, 1)
+	drv.ExpectExec(`UPDATE "users" SET .*`).Regexp()
+
+	var res sql.Result
+	err := drv.Exec(context.Background(), `DELETE FROM "users" WHERE "id" = $1 AND "name" = $2`,
+		[]any{int64(7), nullName{sql.NullString{String: "taurus", Valid: true}}}, &res)
+	if err != nil {
+		t.Fatalf("参数匹配失败: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Fatalf("RowsAffected不正确: %d", n)
+	}
+	if err := drv.Exec(context.Background(), `INSERT INTO "users" ("id") VALUES ($1)`, []any{1}, nil); err == nil {
+		t.Fatal("没有匹配预期的调用应返回错误")
+	}
+	err = drv.ExpectationsWereMet()
+	if err == nil || !strings.Contains(err.Error(), "UPDATE") || !strings.Contains(err.Error(), "unexpected exec") {
+		t.Fatalf("应该报告没有执行的预期和没有匹配的调用: %v", err)
+	}
+	if calls := drv.Calls(); len(calls) != 2 || calls[0].Kind != KindExec || calls[1].Err == nil {
+		t.Fatalf("记录的调用不正确: %+v", calls)
+	}
+}
+
+func TestDriverTx(t *testing.T) {
+	drv := New(dialect.PostgreSQL)
+	conflict := errors.New("duplicate key")
+	drv.ExpectExec(`INSERT INTO "users" ("name") VALUES ($1)`).WithArgs("taurus").WillReturnError(conflict)
+
+	tx, err := drv.Tx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Exec(context.Background(), `INSERT INTO "users" ("name") VALUES ($1)`, []any{"taurus"}, nil)
+	if !errors.Is(err, conflict) {
+		t.Fatalf("应返回预期的错误: %v", err)
+	}
+	if err := entitysql.Rollback(tx, err); !errors.Is(err, conflict) {
+		t.Fatalf("回滚后应返回原始错误: %v", err)
+	}
+	txs := drv.Txs()
+	if len(txs) != 1 || txs[0].State() != TxRolledBack || drv.Calls()[0].Tx != txs[0] {
+		t.Fatalf("事务的状态不正确: %+v", txs)
+	}
+	if err := tx.Commit(); err == nil {
+		t.Fatal("已经回滚的事务不能提交")
+	}
+	if err := tx.Exec(context.Background(), "SELECT 1", nil, nil); err == nil {
+		t.Fatal("已经结束的事务不能执行SQL")
+	}
+
+	drv.WillFailBegin(errors.New("connection refused"))
+	if _, err := drv.Tx(context.Background()); err == nil {
+		t.Fatal("应返回开始事务的错误")
+	}
+}
+
+func TestScanInto(t *testing.T) {
+	type user struct {
+		ID   int64
+		Name *string
+	}
+	drv := New(dialect.PostgreSQL)
+	drv.ExpectQuery(`SELECT id, name FROM users`).
+		WillReturnRows(NewRows("id", "name").AddRow(int64(1), []byte("taurus")).AddRow(int64(2), nil))
+	users, err := entitysql.ScanInto[user](context.Background(), drv, `SELECT id, name FROM users`)
+	if err != nil {
+		t.Fatalf("ScanInto失败: %v", err)
+	}
+	if len(users) != 2 || *users[0].Name != "taurus" || users[1].Name != nil {
+		t.Fatalf("ScanInto的结果不正确: %+v", users)
+	}
+}
+
+func TestDriverNotify(t *testing.T) {
+	drv := New(dialect.PostgreSQL)
+	l, _ := drv.Listener(context.Background())
+	drv.Notify("users", "ignored")
+	if err := l.Listen(context.Background(), "users"); err != nil {
+		t.Fatalf("Listen 返回了意外错误: %v", err)
+	}
+	drv.Notify("users", "1")
+	drv.Notify("blogs", "2")
+	drv.Reconnect()
+	if n := <-l.Notify(); n == nil || n.Channel != "users" || n.Payload != "1" {
+		t.Fatalf("收到的通知不正确: %+v", n)
+	}
+	if n := <-l.Notify(); n != nil {
+		t.Fatalf("重新连接后应收到nil: %+v", n)
+	}
+	if drv.Listening("users") != 1 {
+		t.Fatal("监听的连接数量不正确")
+	}
+	l.Close()
+	if _, ok := <-l.Notify(); ok || drv.Listening("users") != 0 {
+		t.Fatal("Close之后通道应被关闭")
+	}
+}
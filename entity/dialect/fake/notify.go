@@ -0,0 +1,129 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// Listener 内存中的监听连接，实现了[dialect.Listener]，通过[Driver.Notify]发送通知。
+type Listener struct {
+	drv      *Driver
+	mu       sync.Mutex
+	channels map[string]bool
+	notify   chan *dialect.Notification
+	closed   bool
+}
+
+// Listener 实现了[dialect.Notifier]，创建一个内存中的监听连接。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//
+// Returns:
+//
+//	0: 监听连接。
+//	1: 错误信息，总是nil。
+func (d *Driver) Listener(ctx context.Context) (dialect.Listener, error) {
+	l := &Listener{
+		drv:      d,
+		channels: map[string]bool{},
+		notify:   make(chan *dialect.Notification, 64),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, l)
+	return l, nil
+}
+
+// Notify 向监听了channel的连接发送通知，和NOTIFY一样，没有监听的连接时通知会被丢弃。
+//
+// Params:
+//
+//   - channel: 通知的频道。
+//   - payload: 通知的内容。
+func (d *Driver) Notify(channel string, payload string) {
+	for _, l := range d.activeListeners() {
+		l.send(channel, &dialect.Notification{Channel: channel, Payload: payload})
+	}
+}
+
+// Reconnect 模拟监听的连接断开后重新建立，所有的监听连接都会收到nil。
+func (d *Driver) Reconnect() {
+	for _, l := range d.activeListeners() {
+		l.send("", nil)
+	}
+}
+
+// Listening 返回正在监听channel的连接的数量。
+//
+// Params:
+//
+//   - channel: 通知的频道。
+func (d *Driver) Listening(channel string) int {
+	n := 0
+	for _, l := range d.activeListeners() {
+		l.mu.Lock()
+		if l.channels[channel] {
+			n++
+		}
+		l.mu.Unlock()
+	}
+	return n
+}
+
+// activeListeners 返回没有关闭的监听连接。
+func (d *Driver) activeListeners() []*Listener {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ls := make([]*Listener, 0, len(d.listeners))
+	for _, l := range d.listeners {
+		l.mu.Lock()
+		if !l.closed {
+			ls = append(ls, l)
+		}
+		l.mu.Unlock()
+	}
+	return ls
+}
+
+// Listen 实现了[dialect.Listener]。
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.channels[channel] = true
+	return nil
+}
+
+// Notify 实现了[dialect.Listener]。
+func (l *Listener) Notify() <-chan *dialect.Notification {
+	return l.notify
+}
+
+// Close 实现了[dialect.Listener]。
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.notify)
+	}
+	return nil
+}
+
+// send 发送通知，channel为空时发送给所有的连接，通道满了时会等待接收。
+//
+// Params:
+//
+//   - channel: 通知的频道。
+//   - n: 通知。
+func (l *Listener) send(channel string, n *dialect.Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed || (channel != "" && !l.channels[channel]) {
+		return
+	}
+	l.notify <- n
+}
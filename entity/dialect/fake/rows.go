@@ -0,0 +1,156 @@
+package fake
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Rows Query返回的行，实现了[dialect.RowsScanner]。
+type Rows struct {
+	columns []string
+	values  [][]any
+	// pos 当前行的位置，Next之前为-1。
+	pos    int
+	closed bool
+	err    error
+}
+
+// NewRows 创建返回的行。
+//
+// Params:
+//
+//   - columns: 列名。
+//
+// Returns:
+//
+//	0: 返回的行。
+func NewRows(columns ...string) *Rows {
+	return &Rows{columns: columns, pos: -1}
+}
+
+// AddRow 添加一行，值的数量需要和列的数量一致。
+//
+// Params:
+//
+//   - values: 每一列的值。
+func (r *Rows) AddRow(values ...any) *Rows {
+	if len(values) != len(r.columns) {
+		panic(fmt.Sprintf("fake: expected %d values, got %d", len(r.columns), len(values)))
+	}
+	r.values = append(r.values, values)
+	return r
+}
+
+// WillReturnError 设置遍历完全部的行之后Err返回的错误。
+//
+// Params:
+//
+//   - err: 错误。
+func (r *Rows) WillReturnError(err error) *Rows {
+	r.err = err
+	return r
+}
+
+// Close 实现了[dialect.RowsScanner]。
+func (r *Rows) Close() error {
+	r.closed = true
+	return nil
+}
+
+// ColumnTypes 实现了[dialect.RowsScanner]，fake中没有列的类型信息，总是返回nil。
+func (r *Rows) ColumnTypes() ([]*sql.ColumnType, error) {
+	return nil, nil
+}
+
+// Columns 实现了[dialect.RowsScanner]。
+func (r *Rows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+// Err 实现了[dialect.RowsScanner]。
+func (r *Rows) Err() error {
+	if r.pos >= len(r.values) {
+		return r.err
+	}
+	return nil
+}
+
+// Next 实现了[dialect.RowsScanner]。
+func (r *Rows) Next() bool {
+	if r.closed || r.pos >= len(r.values) {
+		return false
+	}
+	r.pos++
+	return r.pos < len(r.values)
+}
+
+// NextResultSet 实现了[dialect.RowsScanner]，fake只支持一个结果集。
+func (r *Rows) NextResultSet() bool {
+	return false
+}
+
+// Scan 实现了[dialect.RowsScanner]，dest实现了sql.Scanner时调用Scan，
+// 否则把值赋给dest指向的变量，类型不同时会尝试转换。
+func (r *Rows) Scan(dest ...any) error {
+	if r.pos < 0 || r.pos >= len(r.values) {
+		return fmt.Errorf("fake: Scan called without calling Next")
+	}
+	row := r.values[r.pos]
+	if len(dest) != len(row) {
+		return fmt.Errorf("fake: expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		if err := assign(d, row[i]); err != nil {
+			return fmt.Errorf("fake: scan column %q: %w", r.columns[i], err)
+		}
+	}
+	return nil
+}
+
+// clone 复制行，同一个Rows可以被多个预期使用。
+func (r *Rows) clone() *Rows {
+	return &Rows{columns: r.columns, values: r.values, pos: -1, err: r.err}
+}
+
+// assign 把值赋给dest。
+func assign(dest any, value any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination not a pointer")
+	}
+	dv = dv.Elem()
+	if value == nil {
+		switch dv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		return fmt.Errorf("converting NULL to %s is unsupported", dv.Type())
+	}
+	sv := reflect.ValueOf(value)
+	if dv.Kind() == reflect.Pointer {
+		p := reflect.New(dv.Type().Elem())
+		if err := assign(p.Interface(), value); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+	switch {
+	case sv.Type().AssignableTo(dv.Type()):
+		dv.Set(sv)
+	case sv.Type().ConvertibleTo(dv.Type()) && (sv.Kind() == reflect.String) == (dv.Kind() == reflect.String):
+		dv.Set(sv.Convert(dv.Type()))
+	case sv.Kind() == reflect.String && dv.Type() == reflect.TypeOf([]byte(nil)):
+		dv.SetBytes([]byte(sv.String()))
+	case sv.Type() == reflect.TypeOf([]byte(nil)) && dv.Kind() == reflect.String:
+		dv.SetString(string(sv.Bytes()))
+	default:
+		return fmt.Errorf("unsupported Scan, storing %T into %s", value, dv.Type())
+	}
+	return nil
+}
@@ -23,6 +23,8 @@ type (
 	Driver struct {
 		Conn
 		dialect dialect.DbDriver
+		// dsn 创建连接使用的连接字符串，用于创建LISTEN等需要独占的连接。
+		dsn string
 	}
 
 	// Conn 当前数据库连接，这个会在Driver.BeginTx()中被初始化，用于存放sql.Tx。
@@ -57,6 +59,24 @@ func (d Driver) Dialect() dialect.DbDriver {
 	return d.dialect
 }
 
+// SetDSN 设置创建连接使用的连接字符串。
+//
+// Params:
+//
+//   - dsn: 连接字符串。
+func (d *Driver) SetDSN(dsn string) {
+	d.dsn = dsn
+}
+
+// DSN 返回创建连接使用的连接字符串，没有设置时为空字符串。
+//
+// Returns:
+//
+//	0: 连接字符串。
+func (d Driver) DSN() string {
+	return d.dsn
+}
+
 // DB 返回数据库连接。
 //
 // Returns:
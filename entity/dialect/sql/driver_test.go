@@ -63,3 +63,17 @@ func TestConnQueryPassThroughError(t *testing.T) {
 		t.Fatal("Query 未调用底层 QueryContext")
 	}
 }
+
+func TestTxCopyFrom(t *testing.T) {
+	if got := copyQuery("public", "user", []string{"id", "na\"me"}); got != `COPY "public"."user" ("id", "na""me") FROM STDIN` {
+		t.Fatalf("COPY 语句不正确: %s", got)
+	}
+	if got := copyQuery("", "user", []string{"id"}); got != `COPY "user" ("id") FROM STDIN` {
+		t.Fatalf("COPY 语句不正确: %s", got)
+	}
+
+	tx := Tx{dialect: dialect.MySQL}
+	if _, err := tx.CopyFrom(context.Background(), "", "user", []string{"id"}, nil); err == nil {
+		t.Fatal("非 PostgreSQL 使用 COPY 应返回错误")
+	}
+}
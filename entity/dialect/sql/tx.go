@@ -1,7 +1,11 @@
 package sql
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"fmt"
+	"strings"
 
 	"github.com/zodileap/taurus_go/entity/dialect"
 )
@@ -21,3 +25,67 @@ type Tx struct {
 func (d Tx) Dialect() dialect.DbDriver {
 	return d.dialect
 }
+
+// CopyFrom dialect.Copier的实现，使用PostgreSQL的COPY ... FROM STDIN批量写入数据。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - schema: 模式名称，为空时使用默认的schema。
+//   - table: 表名。
+//   - columns: 写入的列。
+//   - rows: 每一行的值，顺序和columns一致。
+//
+// Returns:
+//
+//	0: 写入的行数。
+//	1: 错误信息。
+func (d Tx) CopyFrom(ctx context.Context, schema string, table string, columns []string, rows [][]any) (int64, error) {
+	if d.dialect != dialect.PostgreSQL {
+		return 0, fmt.Errorf("dialect/sql: COPY is not supported by %s", d.dialect)
+	}
+	tx, ok := d.Tx.(*sql.Tx)
+	if !ok {
+		return 0, fmt.Errorf("dialect/sql: invalid type %T. expect *sql.Tx", d.Tx)
+	}
+	stmt, err := tx.PrepareContext(ctx, copyQuery(schema, table, columns))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for _, row := range rows {
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return 0, err
+		}
+	}
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// copyQuery 生成COPY ... FROM STDIN语句，PostgreSQL驱动在Prepare时根据该语句进入COPY模式。
+func copyQuery(schema string, table string, columns []string) string {
+	b := strings.Builder{}
+	b.WriteString("COPY ")
+	if schema != "" {
+		b.WriteString(quoteIdent(schema))
+		b.WriteByte('.')
+	}
+	b.WriteString(quoteIdent(table))
+	b.WriteString(" (")
+	for i, c := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdent(c))
+	}
+	b.WriteString(") FROM STDIN")
+	return b.String()
+}
+
+// quoteIdent 使用双引号包裹标识符。
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
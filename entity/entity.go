@@ -2,6 +2,7 @@ package entity
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 
 	"github.com/zodileap/taurus_go/entity/dialect"
 )
@@ -10,10 +11,12 @@ func init() {
 	defalutBatchSize := 65535
 	defalutSqlConsole := false
 	defalutSqlLogger := "entity"
+	defalutCopyThreshold := 1000
 	config = &Config{
-		BatchSize:  &defalutBatchSize,
-		SqlConsole: &defalutSqlConsole,
-		SqlLogger:  &defalutSqlLogger,
+		BatchSize:     &defalutBatchSize,
+		SqlConsole:    &defalutSqlConsole,
+		SqlLogger:     &defalutSqlLogger,
+		CopyThreshold: &defalutCopyThreshold,
 	}
 }
 
@@ -58,7 +61,11 @@ type (
 		// 连接的标签。
 		Tag string
 		// 数据库驱动
-		Type     dialect.DbDriver
+		Type dialect.DbDriver
+		// Schema 数据库中实体表默认所在的模式，为空时使用public。
+		Schema string
+		// Triggers 触发器，其中的字符串会直接写入生成的SQL，
+		// 也可以在数据库的Triggers()方法中通过[InitTrigger]定义，codegen会检查触发器的定义。
 		Triggers []TriggerConfig
 	}
 	DbInterface interface {
@@ -68,6 +75,8 @@ type (
 	TriggerConfig struct {
 		// 触发器名称
 		Name string
+		// Schema 触发器作用的表和触发器函数所在的模式，为空时使用[DbConfig]中的Schema。
+		Schema string
 		// 触发器作用的表
 		Table string
 		// 触发时机：BEFORE, AFTER
@@ -78,9 +87,11 @@ type (
 		Level string
 		// 触发器函数
 		Function string
+		// Declare 触发器函数DECLARE中声明的变量。
+		Declare string
 		// 触发条件(WHEN clause)
 		Condition string
-		// 触发器函数的参数列表
+		// 触发器函数的参数列表，每一个参数都是SQL的字符串常量，例如"''audit''"。
 		Arguments []string
 	}
 )
@@ -121,6 +132,18 @@ type (
 		// Comment entity的注释。
 		// 在生成的sql中会用于生成表的注释。
 		Comment string
+		// Schema entity的表所在的模式，为空时使用[DbConfig]中的Schema。
+		Schema string
+		// TenantField 租户字段在数据库中的名称，比如"tenant_id"。
+		// 设置后，查询、更新和删除都会加上租户的过滤条件，新增时会设置租户字段的值，
+		// 租户通过[WithTenant]添加到context中，context中没有租户时会返回错误。
+		TenantField string
+		// Partition 表的分区配置，设置后会生成PostgreSQL的声明式分区表。
+		Partition *PartitionConfig
+		// History 是否记录表的历史，开启后会生成"<表名>_history"历史表和记录历史的触发器，
+		// 每次新增、更新和删除都会记录操作类型、操作前后的行、操作人和操作时间，
+		// 操作人通过[WithActor]添加到context中。
+		History bool
 	}
 )
 
@@ -132,6 +155,16 @@ func (Entity) Fields() []FieldBuilder {
 	return nil
 }
 
+// Indexes 实体表的索引，需要多个字段、表达式或者部分索引时，在实体中覆盖这个方法。
+func (Entity) Indexes() []IndexBuilder {
+	return nil
+}
+
+// Checks 实体表的CHECK约束，约束涉及多个字段时，在实体中覆盖这个方法。
+func (Entity) Checks() []CheckBuilder {
+	return nil
+}
+
 // ORM生成中实体表中的字段。
 type (
 	FieldValue driver.Value
@@ -192,6 +225,10 @@ type (
 		AttrType string `json:"attr_type,omitempty"`
 		// Size 字段的长度大小。
 		Size int64 `json:"size,omitempty"`
+		// MinLen 字段的最小长度，0表示没有限制。
+		MinLen int64 `json:"min_len,omitempty"`
+		// Enums 字段可以选择的值，为空表示没有限制。
+		Enums []string `json:"enums,omitempty"`
 		// Required 是否是必填字段，如果为true,在数据表中的表现就是这个字段非空。
 		Required bool `json:"required,omitempty"`
 		// Primary 字段是否为主键,大于等于1的才会被认为是主键。
@@ -204,20 +241,28 @@ type (
 		Default bool `json:"default,omitempty"`
 		// DefaultValue 字段默认值的字符串形式。
 		DefaultValue string `json:"default_value,omitempty"`
+		// Generated 生成列的表达式，设置后字段是GENERATED ALWAYS AS (Generated) STORED，
+		// 值由数据库计算，新增和更新时不会写入，新增后通过RETURNING读取。
+		Generated string `json:"generated,omitempty"`
+		// Identity 字段是否是GENERATED BY DEFAULT AS IDENTITY标识列，
+		// 没有设置值时由数据库生成，新增后通过RETURNING读取。
+		Identity bool `json:"identity,omitempty"`
 		// Locked 字段是否被锁定，如果为true,则不能被修改。
 		Locked bool `json:"locked,omitempty"`
 		// Sequence 字段的序列，
-		// 不是所有的字段类型都可以设置序列，内置的类型中只有Int(Int16,Int32,Int64)
+		// 不是所有的字段类型都可以设置序列，内置的类型中只有Int(Int16,Int32,Int64)、UUID和Varchar
 		// 才有Sequence()方法，自定义字段要看是否实现了设置序列的相关方法。
-		Sequence Sequence `json:"validators,omitempty"`
+		Sequence Sequence `json:"sequence,omitempty"`
 		// Validators 字段验证函数。
-		Validators []any `json:"sequence,omitempty"`
+		Validators []any `json:"validators,omitempty"`
 		// Depth 字段的值类型的深度，例如[]int64的深度为1，[][]int64的深度为2。
 		Depth int `json:"depth,omitempty"`
 		// Uniques 字段的唯一约束信息。序号相同的字段构成联合唯一约束
 		Uniques []int `json:"uniques,omitempty"`
 		// CheckConstraint 存储字段的CHECK约束语句
 		CheckConstraint string `json:"check_constraint,omitempty"`
+		// CheckExpr 序列化后的check.Cond，通过CheckExpr()设置，codegen会生成CheckConstraint和保存之前的检查。
+		CheckExpr json.RawMessage `json:"check_expr,omitempty"`
 		// Indexes 字段的索引信息。key是索引序号，如果序号相同表示是联合索引
 		Indexes []int `json:"indexes,omitempty"`
 		// IndexName 索引名称。如果为空，会根据表名和字段名自动生成。
@@ -230,7 +275,8 @@ type (
 	Sequence struct {
 		// Name 序列的名称，不能为空字符串。
 		Name *string
-		// Mode 序列的模式，目前只支持TSID。
+		// Mode 序列的模式。TSID由数据库生成，需要设置Name；
+		// Snowflake、UUIDv7、ULID在Go中生成，不需要设置Name。
 		Mode string
 	}
 )
@@ -242,7 +288,7 @@ func NewSequence(name string) Sequence {
 	}
 	return Sequence{
 		Name: &name,
-		Mode: "TSID",
+		Mode: SequenceTSID,
 	}
 }
 
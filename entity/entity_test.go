@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDescriptorJSON(t *testing.T) {
+	desc := Descriptor{
+		Name:       "ID",
+		AttrName:   "id",
+		Sequence:   NewSequence("id_seq"),
+		Validators: []any{"v"},
+	}
+	data, err := json.Marshal(desc)
+	if err != nil {
+		t.Fatalf("序列化 Descriptor 失败: %v", err)
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("解析 Descriptor JSON 失败: %v", err)
+	}
+	if string(keys["validators"]) != `["v"]` {
+		t.Fatalf("validators 的值不正确: %s", keys["validators"])
+	}
+	if _, ok := keys["sequence"]; !ok {
+		t.Fatalf("缺少 sequence: %s", data)
+	}
+
+	var got Descriptor
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("反序列化 Descriptor 失败: %v", err)
+	}
+	if got.Sequence.Name == nil || *got.Sequence.Name != "id_seq" {
+		t.Fatalf("Sequence.Name 未保留: %v", got.Sequence.Name)
+	}
+	if got.Sequence.Mode != SequenceTSID {
+		t.Fatalf("Sequence.Mode 未保留: %s", got.Sequence.Mode)
+	}
+	if len(got.Validators) != 1 || got.Validators[0] != "v" {
+		t.Fatalf("Validators 未保留: %v", got.Validators)
+	}
+}
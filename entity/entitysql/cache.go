@@ -0,0 +1,281 @@
+package entitysql
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/tlog"
+)
+
+func init() {
+	// 驱动返回的值中除了基础类型，只有时间需要注册。
+	gob.Register(time.Time{})
+}
+
+// CacheTable 返回按表清除缓存时使用的表名，带有模式名称时为"<模式>.<表名>"。
+//
+// Params:
+//
+//   - ctx: 上下文，通过NewSchemaContext设置的模式优先。
+//   - schema: 实体表所在的模式。
+//   - name: 实体表的名称。
+func CacheTable(ctx context.Context, schema string, name string) string {
+	return qualifiedName(schemaName(ctx, schema), name)
+}
+
+// InvalidateCache 清除使用了这些表的缓存，在事务提交之后调用。
+// 修改已经保存到数据库中，所以清除失败时只记录日志，不返回错误。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - cache: 查询缓存，为nil时不做任何处理。
+//   - tables: 通过CacheTable得到的表名。
+func InvalidateCache(ctx context.Context, cache entity.QueryCache, tables ...string) {
+	if cache == nil || len(tables) == 0 {
+		return
+	}
+	if err := cache.Invalidate(ctx, tables...); err != nil {
+		tlog.Error(*entity.GetConfig().SqlLogger, fmt.Sprintf("invalidate cache of %v: %v", tables, err))
+	}
+}
+
+// Commit 提交事务，提交成功之后清除使用了这些表的缓存。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - tx: 事务。
+//   - cache: 查询缓存，为nil时只提交事务。
+//   - tables: 事务中修改了的表，通过CacheTable得到。
+func Commit(ctx context.Context, tx dialect.Tx, cache entity.QueryCache, tables ...string) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	InvalidateCache(ctx, cache, tables...)
+	return nil
+}
+
+// qualifiedName 返回带有模式名称的表名。
+//
+// Params:
+//
+//   - schema: 模式的名称，为空时只返回表名。
+//   - name: 表名。
+func qualifiedName(schema string, name string) string {
+	if schema != "" {
+		return schema + "." + name
+	}
+	return name
+}
+
+// cacheKey 根据生成的SQL和参数计算缓存的键。
+//
+// Params:
+//
+//   - spec: 生成的SQL和参数。
+func cacheKey(spec SqlSpec) string {
+	h := sha256.New()
+	h.Write([]byte(spec.Query))
+	for _, arg := range spec.Args {
+		fmt.Fprintf(h, "\x00%T=%v", arg, arg)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheTables 返回查询中使用的表，包括联表和子查询中的表。
+func (s *Selector) cacheTables() []string {
+	tables := []string{}
+	add := func(t TableView) {
+		switch t := t.(type) {
+		case *SelectTable:
+			tables = append(tables, qualifiedName(t.schema, t.name))
+		case *Selector:
+			tables = append(tables, t.cacheTables()...)
+		}
+	}
+	for _, t := range s.from {
+		add(t)
+	}
+	for _, j := range s.joins {
+		add(j.table)
+	}
+	return tables
+}
+
+// cachedQuery 查询并缓存结果，缓存中有结果时不查询数据库。
+// 缓存的读写失败时直接查询数据库，不影响查询的结果。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - selector: 选择语句生成器。
+//   - spec: 生成的SQL和参数。
+func (b *queryBuilder) cachedQuery(ctx context.Context, drv dialect.Driver, selector *Selector, spec SqlSpec) error {
+	key := cacheKey(spec)
+	if data, ok, err := b.Cache.Get(ctx, key); err == nil && ok {
+		var res cachedRows
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&res); err == nil {
+			res.pos = -1
+			_, err := b.scanRows(dialect.Rows{RowsScanner: &res})
+			return err
+		}
+	}
+	var rows dialect.Rows
+	if err := drv.Query(ctx, spec.Query, spec.Args, &rows); err != nil {
+		return err
+	}
+	res, err := readRows(rows)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(res); err == nil {
+		_ = b.Cache.Set(ctx, key, buf.Bytes(), b.CacheTTL, selector.cacheTables())
+	}
+	_, err = b.scanRows(dialect.Rows{RowsScanner: res})
+	return err
+}
+
+// cachedRows 缓存的查询结果，实现了[dialect.RowsScanner]。
+type cachedRows struct {
+	Cols   []string
+	Values [][]any
+	// pos 当前行的位置，Next之前为-1。
+	pos int
+}
+
+// readRows 读取全部的行，读取之后关闭rows。
+//
+// Params:
+//
+//   - rows: 查询结果。
+func readRows(rows dialect.Rows) (*cachedRows, error) {
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	res := &cachedRows{Cols: cols, pos: -1}
+	for rows.Next() {
+		values := make([]any, len(cols))
+		dest := make([]any, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res.Values = append(res.Values, values)
+	}
+	return res, rows.Err()
+}
+
+// Close 实现了[dialect.RowsScanner]。
+func (r *cachedRows) Close() error {
+	return nil
+}
+
+// ColumnTypes 实现了[dialect.RowsScanner]，缓存中没有列的类型。
+func (r *cachedRows) ColumnTypes() ([]*sql.ColumnType, error) {
+	return nil, errors.New("entitysql: column types are not cached")
+}
+
+// Columns 实现了[dialect.RowsScanner]。
+func (r *cachedRows) Columns() ([]string, error) {
+	return r.Cols, nil
+}
+
+// Err 实现了[dialect.RowsScanner]。
+func (r *cachedRows) Err() error {
+	return nil
+}
+
+// Next 实现了[dialect.RowsScanner]。
+func (r *cachedRows) Next() bool {
+	if r.pos+1 >= len(r.Values) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+// NextResultSet 实现了[dialect.RowsScanner]。
+func (r *cachedRows) NextResultSet() bool {
+	return false
+}
+
+// Scan 实现了[dialect.RowsScanner]，和database/sql一样把值传给[sql.Scanner]，或者转换为目标的类型。
+func (r *cachedRows) Scan(dest ...any) error {
+	if r.pos < 0 || r.pos >= len(r.Values) {
+		return errors.New("entitysql: Scan called without calling Next")
+	}
+	row := r.Values[r.pos]
+	if len(dest) != len(row) {
+		return fmt.Errorf("entitysql: expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, v := range row {
+		if err := assignValue(dest[i], v); err != nil {
+			return fmt.Errorf("entitysql: Scan error on column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// assignValue 把驱动返回的值赋给Scan的目标。
+//
+// Params:
+//
+//   - dest: Scan的目标。
+//   - value: 驱动返回的值。
+func assignValue(dest any, value any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("destination not a pointer")
+	}
+	dv = dv.Elem()
+	if value == nil {
+		switch dv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		return fmt.Errorf("converting NULL to %s is unsupported", dv.Type())
+	}
+	sv := reflect.ValueOf(value)
+	if dv.Kind() == reflect.Pointer {
+		p := reflect.New(dv.Type().Elem())
+		if err := assignValue(p.Interface(), value); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+	switch {
+	case sv.Type().AssignableTo(dv.Type()):
+		dv.Set(sv)
+	case sv.Type().ConvertibleTo(dv.Type()) && (sv.Kind() == reflect.String) == (dv.Kind() == reflect.String):
+		dv.Set(sv.Convert(dv.Type()))
+	case sv.Kind() == reflect.String && dv.Type() == reflect.TypeOf([]byte(nil)):
+		dv.SetBytes([]byte(sv.String()))
+	case sv.Type() == reflect.TypeOf([]byte(nil)) && dv.Kind() == reflect.String:
+		dv.SetString(string(sv.Bytes()))
+	default:
+		return fmt.Errorf("unsupported Scan, storing %T into %s", value, dv.Type())
+	}
+	return nil
+}
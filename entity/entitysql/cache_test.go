@@ -0,0 +1,135 @@
+package entitysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
+)
+
+// mapCache 测试使用的查询缓存，记录Set时的表和清除缓存时的表。
+type mapCache struct {
+	values      map[string][]byte
+	tables      []string
+	invalidated []string
+}
+
+func (c *mapCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, ok := c.values[key]
+	return v, ok, nil
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tables []string) error {
+	c.values[key] = value
+	c.tables = tables
+	return nil
+}
+
+func (c *mapCache) Invalidate(ctx context.Context, tables ...string) error {
+	c.values = map[string][]byte{}
+	c.invalidated = append(c.invalidated, tables...)
+	return nil
+}
+
+func newCacheTestSpec(c *mapCache, values *[]any) *QuerySpec {
+	spec := NewQuerySpec("users", []FieldName{"id", "name", "created_at"})
+	spec.Entity.Schema = "app"
+	spec.Cache, spec.CacheTTL = c, time.Minute
+	spec.Scan = func(rows dialect.Rows, fields []ScannerField) error {
+		var (
+			id      int
+			name    *string
+			created time.Time
+		)
+		if err := rows.Scan(&id, &name, &created); err != nil {
+			return err
+		}
+		*values = append(*values, id, name, created)
+		return nil
+	}
+	return spec
+}
+
+func TestNewQueryCache(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	drv.ExpectQuery(`SELECT .* FROM "app"."users" .*`).Regexp().WillReturnRows(
+		fake.NewRows("id", "name", "created_at").
+			AddRow(int64(1), "a", now).
+			AddRow(int64(2), nil, now),
+	)
+	c := &mapCache{values: map[string][]byte{}}
+	var first, second []any
+	if err := NewQuery(context.Background(), drv, newCacheTestSpec(c, &first)); err != nil {
+		t.Fatalf("NewQuery 返回了意外错误: %v", err)
+	}
+	if len(c.values) != 1 || !reflect.DeepEqual(c.tables, []string{"app.users"}) {
+		t.Fatalf("查询结果没有按表缓存: %v %v", c.values, c.tables)
+	}
+	if err := NewQuery(context.Background(), drv, newCacheTestSpec(c, &second)); err != nil {
+		t.Fatalf("NewQuery 返回了意外错误: %v", err)
+	}
+	if len(drv.Calls()) != 1 {
+		t.Fatalf("缓存中有结果时不应查询数据库: %d", len(drv.Calls()))
+	}
+	if !reflect.DeepEqual(first, second) || first[0] != 1 || first[4] != (*string)(nil) || !first[2].(time.Time).Equal(now) {
+		t.Fatalf("缓存的结果和查询的结果不一致: %v %v", first, second)
+	}
+
+	c.Invalidate(context.Background(), "app.users")
+	drv.ExpectQuery(`SELECT .* FROM "app"."users" .*`).Regexp().WillReturnRows(fake.NewRows("id", "name", "created_at"))
+	var third []any
+	if err := NewQuery(context.Background(), drv, newCacheTestSpec(c, &third)); err != nil {
+		t.Fatalf("NewQuery 返回了意外错误: %v", err)
+	}
+	if len(drv.Calls()) != 2 || len(third) != 0 {
+		t.Fatal("清除缓存之后应重新查询数据库")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := cacheKey(SqlSpec{Query: "SELECT 1 WHERE a = $1", Args: []any{1}})
+	if a != cacheKey(SqlSpec{Query: "SELECT 1 WHERE a = $1", Args: []any{1}}) {
+		t.Fatal("相同的语句和参数应使用相同的键")
+	}
+	if a == cacheKey(SqlSpec{Query: "SELECT 1 WHERE a = $1", Args: []any{"1"}}) {
+		t.Fatal("参数的类型不同时应使用不同的键")
+	}
+}
+
+func TestCacheTable(t *testing.T) {
+	if got := CacheTable(context.Background(), "app", "users"); got != "app.users" {
+		t.Fatalf("表名不正确: %s", got)
+	}
+	if got := CacheTable(NewSchemaContext(context.Background(), "tenant_1"), "app", "users"); got != "tenant_1.users" {
+		t.Fatalf("context中的模式应优先: %s", got)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	c := &mapCache{values: map[string][]byte{"k": nil}}
+	tx, _ := drv.Tx(context.Background())
+	tx.(*fake.Tx).WillFailCommit(errors.New("commit failed"))
+	if err := Commit(context.Background(), tx, c, "app.users"); err == nil || len(c.invalidated) != 0 {
+		t.Fatalf("提交失败时应返回错误且不清除缓存: %v %v", err, c.invalidated)
+	}
+	tx, _ = drv.Tx(context.Background())
+	if err := Commit(context.Background(), tx, c, "app.users"); err != nil {
+		t.Fatalf("Commit 返回了意外错误: %v", err)
+	}
+	if !reflect.DeepEqual(c.invalidated, []string{"app.users"}) || len(c.values) != 0 {
+		t.Fatalf("提交之后应清除表的缓存: %v", c.invalidated)
+	}
+	if tx.(*fake.Tx).State() != fake.TxCommitted {
+		t.Fatalf("事务应已提交: %v", tx.(*fake.Tx).State())
+	}
+	tx, _ = drv.Tx(context.Background())
+	if err := Commit(context.Background(), tx, nil, "app.users"); err != nil {
+		t.Fatalf("没有缓存时只提交事务: %v", err)
+	}
+}
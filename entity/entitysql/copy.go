@@ -0,0 +1,199 @@
+package entitysql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+const (
+	// copyStagingSuffix 需要读取数据库生成的值时，COPY写入的临时表的后缀。
+	copyStagingSuffix = "_copy"
+	// copyOrderColumn 临时表中记录行顺序的列，INSERT ... RETURNING按照这个顺序返回。
+	copyOrderColumn = "copy_order"
+)
+
+// NewCopy 使用COPY ... FROM STDIN批量写入实体。
+// Returning中的列由数据库生成时，例如序列和默认值，先COPY到临时表，
+// 再通过INSERT ... SELECT ... RETURNING写入实体表并按照行的顺序读取生成的值。
+// 以下情况会使用NewCreate分批INSERT：
+// 事务不支持COPY；某一列只有部分行有值；某一列的参数需要使用SQL函数格式化。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库事务。
+//   - spec: 创建语句的信息。
+func NewCopy(ctx context.Context, drv dialect.Tx, spec *CreateSpec) error {
+	copier, ok := drv.(dialect.Copier)
+	if !ok || drv.Dialect() != dialect.PostgreSQL {
+		return NewCreate(ctx, drv, spec)
+	}
+	columns, rows, ok := spec.copyRows(drv.Dialect())
+	if !ok {
+		return NewCreate(ctx, drv, spec)
+	}
+	schema := spec.schema(ctx)
+	for _, r := range spec.Returning {
+		if !slices.Contains(columns, string(r)) {
+			return spec.copyReturning(ctx, drv, copier, columns, rows)
+		}
+	}
+	logCopy(drv.Dialect(), schema, spec.Entity.Name, columns, len(rows))
+	_, err := copier.CopyFrom(ctx, schema, spec.Entity.Name, columns, rows)
+	return err
+}
+
+// copyReturning 把行COPY到临时表，再写入实体表，并读取Returning中的列。
+// 临时表在写入之后删除，事务回滚时也会被删除。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库事务。
+//   - copier: 支持COPY的事务。
+//   - columns: 写入的列。
+//   - rows: 每一行的值。
+func (s *CreateSpec) copyReturning(ctx context.Context, drv dialect.Tx, copier dialect.Copier, columns []string, rows [][]any) error {
+	d, schema := drv.Dialect(), s.schema(ctx)
+	staging := s.Entity.Name + copyStagingSuffix
+	exec := func(b *Builder) error {
+		sqlSpec := SqlSpec{Query: b.String(), Args: b.args}
+		logSql(&sqlSpec)
+		return drv.Exec(ctx, sqlSpec.Query, sqlSpec.Args, nil)
+	}
+
+	b := &Builder{dialect: d}
+	b.WriteString("CREATE TEMP TABLE ").Ident(staging).WriteString(" ON COMMIT DROP AS SELECT ")
+	b.IdentComma(columns...).WriteString(", 0::bigint AS ").Ident(copyOrderColumn)
+	b.WriteString(" FROM ").WriteSchema(schema).Ident(s.Entity.Name).WriteString(" WITH NO DATA")
+	if err := exec(b); err != nil {
+		return err
+	}
+	ordered := make([][]any, len(rows))
+	for i, row := range rows {
+		ordered[i] = append(append(make([]any, 0, len(row)+1), row...), int64(i))
+	}
+	stagingColumns := append(slices.Clone(columns), copyOrderColumn)
+	logCopy(d, "", staging, stagingColumns, len(ordered))
+	if _, err := copier.CopyFrom(ctx, "", staging, stagingColumns, ordered); err != nil {
+		return err
+	}
+
+	returning := make([]string, len(s.Returning))
+	fields := make([]ScannerField, len(s.Returning))
+	for i, r := range s.Returning {
+		returning[i], fields[i] = string(r), r
+	}
+	b = &Builder{dialect: d}
+	b.WriteString("INSERT INTO ").WriteSchema(schema).Ident(s.Entity.Name).Blank().Wrap(func(b *Builder) {
+		b.IdentComma(columns...)
+	})
+	b.WriteString(" SELECT ").IdentComma(columns...).WriteString(" FROM ").Ident(staging)
+	b.WriteString(" ORDER BY ").Ident(copyOrderColumn).WriteString(" RETURNING ").IdentComma(returning...)
+	sqlSpec := SqlSpec{Query: b.String(), Args: b.args}
+	logSql(&sqlSpec)
+	var res dialect.Rows
+	if err := drv.Query(ctx, sqlSpec.Query, sqlSpec.Args, &res); err != nil {
+		return err
+	}
+	for res.Next() {
+		if err := s.Scan(res, fields); err != nil {
+			res.Close()
+			return err
+		}
+	}
+	if err := res.Err(); err != nil {
+		res.Close()
+		return err
+	}
+	if err := res.Close(); err != nil {
+		return err
+	}
+
+	b = &Builder{dialect: d}
+	b.WriteString("DROP TABLE ").Ident(staging)
+	return exec(b)
+}
+
+// logCopy 记录COPY语句，写入的行数记录在语句后面。
+//
+// Params:
+//
+//   - d: 数据库方言。
+//   - schema: 表所在的模式，为空时不写入模式。
+//   - table: 写入的表。
+//   - columns: 写入的列。
+//   - n: 写入的行数。
+func logCopy(d dialect.DbDriver, schema string, table string, columns []string, n int) {
+	b := &Builder{dialect: d}
+	b.WriteString("COPY ").WriteSchema(schema).Ident(table).Blank().Wrap(func(b *Builder) {
+		b.IdentComma(columns...)
+	})
+	b.WriteString(fmt.Sprintf(" FROM STDIN (%d rows)", n))
+	logSql(&SqlSpec{Query: b.String()})
+}
+
+// copyRows 提取COPY需要写入的列和每一行的值，所有行都没有值的列交给数据库填充默认值。
+//
+// Params:
+//
+//   - dbType: 数据库类型。
+//
+// Returns:
+//
+//	0: 写入的列。
+//	1: 每一行的值。
+//	2: 是否可以使用COPY。
+func (s *CreateSpec) copyRows(dbType dialect.DbDriver) ([]string, [][]any, bool) {
+	values := make([]map[FieldName]*FieldSpec, len(s.Fields))
+	for i, fields := range s.Fields {
+		values[i] = make(map[FieldName]*FieldSpec, len(fields))
+		for _, f := range fields {
+			values[i][f.Name] = f
+		}
+	}
+	columns := []string{}
+	for _, c := range s.Entity.Columns {
+		set := 0
+		for _, row := range values {
+			f, ok := row[c.Name]
+			if !ok || f.Param == nil {
+				continue
+			}
+			if f.ParamFormat != nil && f.ParamFormat(dbType, "$1") != "$1" {
+				return nil, nil, false
+			}
+			set++
+		}
+		switch set {
+		case 0:
+			continue
+		case len(values):
+			columns = append(columns, string(c.Name))
+		default:
+			return nil, nil, false
+		}
+	}
+	rows := make([][]any, len(values))
+	for i, row := range values {
+		rows[i] = make([]any, len(columns))
+		for j, c := range columns {
+			rows[i][j] = row[FieldName(c)].Param
+		}
+	}
+	return columns, rows, true
+}
+
+// IsCopyThreshold 判断新增的实体数量是否达到使用COPY的阈值。
+//
+// Params:
+//
+//   - n: 新增的实体数量。
+func IsCopyThreshold(n int) bool {
+	config := entity.GetConfig()
+	return config.CopyThreshold != nil && *config.CopyThreshold > 0 && n >= *config.CopyThreshold
+}
@@ -0,0 +1,156 @@
+package entitysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
+)
+
+type copyTestTx struct {
+	dialect.Tx
+	table   string
+	columns []string
+	rows    [][]any
+}
+
+func (t *copyTestTx) CopyFrom(ctx context.Context, schema string, table string, columns []string, rows [][]any) (int64, error) {
+	t.table = qualifiedName(schema, table)
+	t.columns = columns
+	t.rows = rows
+	return int64(len(rows)), nil
+}
+
+// newCopyTestTx 创建支持COPY的事务，INSERT使用fake中的驱动，Exec和Query会被记录。
+func newCopyTestTx() (*copyTestTx, *fake.Driver) {
+	drv := fake.New(dialect.PostgreSQL)
+	drv.ExpectExec(`INSERT INTO .*`).Regexp()
+	drv.ExpectQuery(`INSERT INTO .* RETURNING .*`).Regexp()
+	tx, _ := drv.Tx(context.Background())
+	return &copyTestTx{Tx: tx}, drv
+}
+
+func newCopyTestSpec(rows ...[]any) *CreateSpec {
+	spec := NewCreateSpec("users", []FieldName{"id", "name", "age"})
+	for _, row := range rows {
+		fields := make([]*FieldSpec, 0, len(row))
+		for i, c := range spec.Entity.Columns {
+			f := NewFieldSpec(c.Name)
+			if row[i] != nil {
+				f.Param = entity.FieldValue(row[i])
+			}
+			f.ParamFormat = func(dbType dialect.DbDriver, param string) string {
+				return param
+			}
+			fields = append(fields, &f)
+		}
+		spec.Fields = append(spec.Fields, fields)
+	}
+	return spec
+}
+
+func TestNewCopyUsesCopier(t *testing.T) {
+	tx, drv := newCopyTestTx()
+	spec := newCopyTestSpec([]any{nil, "a", 1}, []any{nil, "b", 2})
+
+	if err := NewCopy(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if !reflect.DeepEqual(tx.columns, []string{"name", "age"}) {
+		t.Fatalf("COPY 的列不正确: %v", tx.columns)
+	}
+	if !reflect.DeepEqual(tx.rows, [][]any{{"a", 1}, {"b", 2}}) {
+		t.Fatalf("COPY 的值不正确: %v", tx.rows)
+	}
+	if len(drv.Calls()) != 0 {
+		t.Fatal("使用 COPY 时不应执行 INSERT")
+	}
+}
+
+func TestNewCopyFallsBackToInsert(t *testing.T) {
+	tx, drv := newCopyTestTx()
+	if err := NewCopy(context.Background(), tx, newCopyTestSpec([]any{nil, "a", 1}, []any{nil, "b", nil})); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if calls := drv.Calls(); tx.rows != nil || len(calls) != 1 || calls[0].Kind != fake.KindExec {
+		t.Fatal("部分行没有值时应使用 INSERT")
+	}
+
+	tx, drv = newCopyTestTx()
+	spec := newCopyTestSpec([]any{nil, "a", 1})
+	spec.Fields[0][2].ParamFormat = func(dbType dialect.DbDriver, param string) string {
+		return "to_timestamp(" + param + ")"
+	}
+	if err := NewCopy(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if calls := drv.Calls(); tx.rows != nil || len(calls) != 1 || calls[0].Kind != fake.KindExec {
+		t.Fatal("参数需要使用SQL函数格式化时应使用 INSERT")
+	}
+
+	_, drv = newCopyTestTx()
+	plain, _ := drv.Tx(context.Background())
+	if err := NewCopy(context.Background(), plain, newCopyTestSpec([]any{int64(1), "a", 1})); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if calls := drv.Calls(); len(calls) != 1 || calls[0].Kind != fake.KindExec {
+		t.Fatal("事务不支持 COPY 时应使用 INSERT")
+	}
+}
+
+func TestNewCopyReturning(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	ftx, _ := drv.Tx(context.Background())
+	tx := &copyTestTx{Tx: ftx}
+	drv.ExpectExec(`CREATE TEMP TABLE "users_copy" ON COMMIT DROP AS SELECT "name", "age", 0::bigint AS "copy_order" FROM "app"."users" WITH NO DATA`)
+	drv.ExpectQuery(`INSERT INTO "app"."users" ("name", "age") SELECT "name", "age" FROM "users_copy" ORDER BY "copy_order" RETURNING "id"`).
+		WillReturnRows(fake.NewRows("id").AddRow(int64(7)).AddRow(int64(8)))
+	drv.ExpectExec(`DROP TABLE "users_copy"`)
+
+	// 主键由数据库生成，仍然使用COPY，生成的主键按照行的顺序返回。
+	spec := newCopyTestSpec([]any{nil, "a", 1}, []any{nil, "b", 2})
+	spec.Entity.Schema = "app"
+	spec.Returning = []FieldName{"id"}
+	ids := []int64{}
+	spec.Scan = func(rows dialect.Rows, fields []ScannerField) error {
+		var id int64
+		if len(fields) != 1 || fields[0].String() != "id" {
+			t.Fatalf("返回的列不正确: %v", fields)
+		}
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		return nil
+	}
+	if err := NewCopy(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if tx.table != "users_copy" || !reflect.DeepEqual(tx.columns, []string{"name", "age", "copy_order"}) {
+		t.Fatalf("COPY 的临时表不正确: %s %v", tx.table, tx.columns)
+	}
+	if !reflect.DeepEqual(tx.rows, [][]any{{"a", 1, int64(0)}, {"b", 2, int64(1)}}) {
+		t.Fatalf("COPY 的值不正确: %v", tx.rows)
+	}
+	if !reflect.DeepEqual(ids, []int64{7, 8}) {
+		t.Fatalf("没有读取数据库生成的主键: %v", ids)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 所有Returning的列都有值时直接COPY到实体表。
+	tx, drv = newCopyTestTx()
+	spec = newCopyTestSpec([]any{int64(1), "a", 1})
+	spec.Entity.Schema = "app"
+	spec.Returning = []FieldName{"id"}
+	if err := NewCopy(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCopy 返回了意外错误: %v", err)
+	}
+	if tx.table != "app.users" || len(drv.Calls()) != 0 {
+		t.Fatalf("Returning 的列都有值时应直接 COPY: %s %v", tx.table, drv.Calls())
+	}
+}
@@ -23,7 +23,7 @@ type CreateSpec struct {
 	Fields [][]*FieldSpec
 	// Returning 用于返回的字段。
 	Returning []FieldName
-	// Schema 可选 schema 名称，不设置时使用数据库默认 schema。
+	// Schema 可选 schema 名称，不设置时使用Entity中的Schema。
 	Schema string
 }
 
@@ -57,6 +57,18 @@ func NewCreate(ctx context.Context, drv dialect.Tx, spec *CreateSpec) error {
 	return qb.create(ctx, drv)
 }
 
+// schema 返回插入的表所在的模式，context中指定的模式优先。
+//
+// Params:
+//
+//   - ctx: 上下文。
+func (s *CreateSpec) schema(ctx context.Context) string {
+	if s.Schema != "" {
+		return schemaName(ctx, s.Schema)
+	}
+	return schemaName(ctx, s.Entity.Schema)
+}
+
 // CheckRequired 检查字段是否为空。
 //
 // Params:
@@ -124,7 +136,7 @@ func (b *createBuilder) create(ctx context.Context, drv dialect.Tx) error {
 func (b *createBuilder) inserter(ctx context.Context) (*Inserter, error) {
 	inserter := NewInserter(ctx)
 	inserter.SetDialect(b.builder.dialect)
-	inserter.SetSchema(b.Schema).SetEntity(b.Entity.Name)
+	inserter.SetSchema(b.schema(ctx)).SetEntity(b.Entity.Name)
 	err := b.setColumns(inserter)
 	if err != nil {
 		return nil, err
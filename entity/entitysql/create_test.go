@@ -2,81 +2,17 @@ package entitysql
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 
 	"github.com/zodileap/taurus_go/entity"
 	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
 )
 
-type createTestTx struct {
-	dialect     dialect.DbDriver
-	execCalled  bool
-	queryCalled bool
-}
-
-func (t *createTestTx) Commit() error {
-	return nil
-}
-
-func (t *createTestTx) Rollback() error {
-	return nil
-}
-
-func (t *createTestTx) Dialect() dialect.DbDriver {
-	return t.dialect
-}
-
-func (t *createTestTx) Exec(ctx context.Context, query string, args []any, v any) error {
-	t.execCalled = true
-	return nil
-}
-
-func (t *createTestTx) Query(ctx context.Context, query string, args []any, v *dialect.Rows) error {
-	t.queryCalled = true
-	v.RowsScanner = &createTestRows{remaining: 1}
-	return nil
-}
-
-type createTestRows struct {
-	remaining int
-}
-
-func (r *createTestRows) Close() error {
-	return nil
-}
-
-func (r *createTestRows) ColumnTypes() ([]*sql.ColumnType, error) {
-	return nil, nil
-}
-
-func (r *createTestRows) Columns() ([]string, error) {
-	return nil, nil
-}
-
-func (r *createTestRows) Err() error {
-	return nil
-}
-
-func (r *createTestRows) Next() bool {
-	if r.remaining == 0 {
-		return false
-	}
-	r.remaining--
-	return true
-}
-
-func (r *createTestRows) NextResultSet() bool {
-	return false
-}
-
-func (r *createTestRows) Scan(dest ...any) error {
-	return nil
-}
-
 func TestNewCreateReturnsErrorForUnsupportedReturningDialect(t *testing.T) {
-	tx := &createTestTx{dialect: dialect.MySQL}
+	drv := fake.New(dialect.MySQL)
+	tx, _ := drv.Tx(context.Background())
 
 	err := NewCreate(context.Background(), tx, newCreateSpecWithReturning(func(row dialect.Rows, selects []ScannerField) error {
 		return nil
@@ -84,13 +20,16 @@ func TestNewCreateReturnsErrorForUnsupportedReturningDialect(t *testing.T) {
 	if !errors.Is(err, ErrReturningUnsupported) {
 		t.Fatalf("期望 ErrReturningUnsupported，实际 %v", err)
 	}
-	if tx.execCalled || tx.queryCalled {
-		t.Fatalf("返回不支持错误时不应执行 SQL，exec=%v query=%v", tx.execCalled, tx.queryCalled)
+	if calls := drv.Calls(); len(calls) != 0 {
+		t.Fatalf("返回不支持错误时不应执行 SQL: %+v", calls)
 	}
 }
 
 func TestNewCreateWithReturningQueriesAndScans(t *testing.T) {
-	tx := &createTestTx{dialect: dialect.PostgreSQL}
+	drv := fake.New(dialect.PostgreSQL)
+	drv.ExpectQuery(`INSERT INTO .* RETURNING .*`).Regexp().
+		WillReturnRows(fake.NewRows("id").AddRow(int64(1)))
+	tx, _ := drv.Tx(context.Background())
 	scanCalled := false
 
 	err := NewCreate(context.Background(), tx, newCreateSpecWithReturning(func(row dialect.Rows, selects []ScannerField) error {
@@ -103,11 +42,9 @@ func TestNewCreateWithReturningQueriesAndScans(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewCreate 返回了意外错误: %v", err)
 	}
-	if !tx.queryCalled {
-		t.Fatal("RETURNING 查询应走 Query")
-	}
-	if tx.execCalled {
-		t.Fatal("RETURNING 查询不应走 Exec")
+	calls := drv.Calls()
+	if len(calls) != 1 || calls[0].Kind != fake.KindQuery {
+		t.Fatalf("RETURNING 查询应走 Query，不应走 Exec: %+v", calls)
 	}
 	if !scanCalled {
 		t.Fatal("RETURNING 查询应调用 Scan")
@@ -131,3 +68,49 @@ func newCreateSpecWithReturning(scan Scanner) *CreateSpec {
 		Scan:      scan,
 	}
 }
+
+func TestNewCreateOmitsDefaultColumns(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	drv.ExpectQuery(`INSERT INTO .* RETURNING .*`).Regexp().
+		WillReturnRows(fake.NewRows("id", "created_at").AddRow(int64(1), nil).AddRow(int64(2), nil))
+	tx, _ := drv.Tx(context.Background())
+	name := func(v string) *FieldSpec {
+		f := NewFieldSpec("name")
+		f.Param = entity.FieldValue(v)
+		f.ParamFormat = func(dbType dialect.DbDriver, param string) string {
+			return param
+		}
+		return &f
+	}
+	spec := &CreateSpec{
+		Entity: &EntitySpec{
+			Name:    "users",
+			Columns: NewFieldSpecs("id", "name", "created_at"),
+		},
+		Fields:    [][]*FieldSpec{{name("a")}, {name("b")}},
+		Returning: []FieldName{"id", "created_at"},
+		Scan: func(rows dialect.Rows, fields []ScannerField) error {
+			var id int64
+			var created any
+			return rows.Scan(&id, &created)
+		},
+	}
+	if err := NewCreate(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCreate 返回了意外错误: %v", err)
+	}
+	want := `INSERT INTO "users" ("name") VALUES ($1), ($2) RETURNING "id", "created_at"`
+	if calls := drv.Calls(); len(calls) != 1 || calls[0].Query != want {
+		t.Fatalf("所有行都使用默认值的列不应出现在插入语句中: %+v", calls)
+	}
+
+	drv.ExpectQuery(`INSERT INTO .* RETURNING .*`).Regexp().
+		WillReturnRows(fake.NewRows("id", "created_at").AddRow(int64(3), nil))
+	spec.Fields = [][]*FieldSpec{{}}
+	if err := NewCreate(context.Background(), tx, spec); err != nil {
+		t.Fatalf("NewCreate 返回了意外错误: %v", err)
+	}
+	want = `INSERT INTO "users" DEFAULT VALUES RETURNING "id", "created_at"`
+	if calls := drv.Calls(); len(calls) != 2 || calls[1].Query != want {
+		t.Fatalf("所有列都使用默认值时应使用DEFAULT VALUES: %+v", calls)
+	}
+}
@@ -0,0 +1,144 @@
+package entitysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/tlog"
+)
+
+// cursorSeq 用于生成游标的名字，避免同一连接中游标重名。
+var cursorSeq atomic.Uint64
+
+// NewCursor 分批查询实体，每读取batchSize行后调用一次flush，
+// 返回的结果不会一次全部读入内存。
+// PostgreSQL在事务中使用服务端游标，每次FETCH batchSize行；
+// 其他数据库直接逐行读取结果集。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - spec: 查询的信息，Scan会对每一行调用。
+//   - batchSize: 每批读取的行数。
+//   - flush: 每批读取完成后调用，返回错误时停止查询。
+func NewCursor(ctx context.Context, drv dialect.Driver, spec *QuerySpec, batchSize int, flush func() error) error {
+	if batchSize <= 0 {
+		return entity.Err_0100030008.Sprintf(batchSize)
+	}
+	builder := NewDialect(drv.Dialect())
+	qb := queryBuilder{QuerySpec: spec, entityBuilder: entityBuilder{builder: builder}}
+	selector, err := qb.selector(ctx)
+	if err != nil {
+		return err
+	}
+	sqlSpec, err := selector.Query()
+	if err != nil {
+		return err
+	}
+	var c *cursor
+	if drv.Dialect() == dialect.PostgreSQL {
+		c = &cursor{name: fmt.Sprintf("entity_cursor_%d", cursorSeq.Add(1))}
+	}
+	err = c.run(ctx, drv, &qb, &sqlSpec, batchSize, flush)
+	if errors.Is(err, ErrQueryStop) {
+		return nil
+	}
+	return err
+}
+
+// cursor PostgreSQL的服务端游标。为nil时表示不使用游标。
+type cursor struct {
+	name string
+}
+
+// run 执行查询并分批调用flush。
+func (c *cursor) run(ctx context.Context, drv dialect.Driver, qb *queryBuilder, spec *SqlSpec, batchSize int, flush func() error) error {
+	if c == nil {
+		return scanBatches(ctx, drv, qb, spec, batchSize, flush)
+	}
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.fetch(ctx, tx, qb, spec, batchSize, flush); err != nil {
+		return Rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// fetch 声明游标，并每次FETCH batchSize行，直到没有剩余的行。
+func (c *cursor) fetch(ctx context.Context, tx dialect.Tx, qb *queryBuilder, spec *SqlSpec, batchSize int, flush func() error) error {
+	declare := &SqlSpec{
+		Query: fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", c.name, spec.Query),
+		Args:  spec.Args,
+	}
+	logSql(declare)
+	if err := tx.Exec(ctx, declare.Query, declare.Args, nil); err != nil {
+		return err
+	}
+	fetch := &SqlSpec{Query: fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, c.name)}
+	for {
+		logSql(fetch)
+		var rows dialect.Rows
+		if err := tx.Query(ctx, fetch.Query, nil, &rows); err != nil {
+			return err
+		}
+		n, err := qb.scanRows(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if n < batchSize {
+			break
+		}
+	}
+	return tx.Exec(ctx, fmt.Sprintf("CLOSE %s", c.name), nil, nil)
+}
+
+// scanBatches 不使用游标时，逐行读取结果集，每batchSize行调用一次flush。
+func scanBatches(ctx context.Context, drv dialect.Driver, qb *queryBuilder, spec *SqlSpec, batchSize int, flush func() error) error {
+	logSql(spec)
+	var rows dialect.Rows
+	if err := drv.Query(ctx, spec.Query, spec.Args, &rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	n := 0
+	scan := qb.Scan
+	qb.Scan = func(row dialect.Rows, selects []ScannerField) error {
+		if err := scan(row, selects); err != nil {
+			return err
+		}
+		if n++; n%batchSize == 0 {
+			return flush()
+		}
+		return nil
+	}
+	defer func() { qb.Scan = scan }()
+	if _, err := qb.scanRows(rows); err != nil {
+		return err
+	}
+	if n%batchSize != 0 {
+		return flush()
+	}
+	return nil
+}
+
+// logSql 开启SqlConsole时，输出执行的sql。
+func logSql(spec *SqlSpec) {
+	config := entity.GetConfig()
+	if *(config.SqlConsole) {
+		tlog.Debug(*config.SqlLogger, fmt.Sprintf("sql: %s", spec.Query))
+		tlog.Debug(*config.SqlLogger, fmt.Sprintf("args: %v", spec.Args))
+	}
+}
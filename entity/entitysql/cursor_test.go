@@ -0,0 +1,141 @@
+package entitysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+type cursorTestDriver struct {
+	dialect   dialect.DbDriver
+	batches   [][][]any
+	queries   []string
+	committed bool
+}
+
+func (d *cursorTestDriver) Exec(ctx context.Context, query string, args []any, v any) error {
+	d.queries = append(d.queries, query)
+	return nil
+}
+
+func (d *cursorTestDriver) Query(ctx context.Context, query string, args []any, v *dialect.Rows) error {
+	d.queries = append(d.queries, query)
+	var values [][]any
+	if len(d.batches) > 0 {
+		values, d.batches = d.batches[0], d.batches[1:]
+	}
+	v.RowsScanner = &rawTestRows{columns: []string{"id"}, values: values, index: -1}
+	return nil
+}
+
+func (d *cursorTestDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d, nil
+}
+
+func (d *cursorTestDriver) Close() error {
+	return nil
+}
+
+func (d *cursorTestDriver) Dialect() dialect.DbDriver {
+	return d.dialect
+}
+
+func (d *cursorTestDriver) Commit() error {
+	d.committed = true
+	return nil
+}
+
+func (d *cursorTestDriver) Rollback() error {
+	return nil
+}
+
+func newCursorTestSpec(ids *[]int64) *QuerySpec {
+	spec := NewQuerySpec("users", []FieldName{"id"})
+	spec.Scan = func(rows dialect.Rows, selects []ScannerField) error {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		*ids = append(*ids, id)
+		return nil
+	}
+	return spec
+}
+
+func TestNewCursorFetchesInBatches(t *testing.T) {
+	drv := &cursorTestDriver{
+		dialect: dialect.PostgreSQL,
+		batches: [][][]any{{{int64(1)}, {int64(2)}}, {{int64(3)}}},
+	}
+	ids := []int64{}
+	batches := [][]int64{}
+	err := NewCursor(context.Background(), drv, newCursorTestSpec(&ids), 2, func() error {
+		batches = append(batches, ids)
+		ids = []int64{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewCursor 返回了意外错误: %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatalf("分批结果不正确: %v", batches)
+	}
+	if len(drv.queries) != 4 ||
+		!strings.HasPrefix(drv.queries[0], "DECLARE entity_cursor_") ||
+		!strings.HasPrefix(drv.queries[1], "FETCH FORWARD 2 FROM entity_cursor_") ||
+		!strings.HasPrefix(drv.queries[3], "CLOSE entity_cursor_") {
+		t.Fatalf("执行的SQL不正确: %v", drv.queries)
+	}
+	if !drv.committed {
+		t.Fatal("游标查询结束后应提交事务")
+	}
+}
+
+func TestNewCursorWithoutServerCursor(t *testing.T) {
+	drv := &cursorTestDriver{
+		dialect: dialect.MySQL,
+		batches: [][][]any{{{int64(1)}, {int64(2)}, {int64(3)}}},
+	}
+	ids := []int64{}
+	flushed := []int{}
+	err := NewCursor(context.Background(), drv, newCursorTestSpec(&ids), 2, func() error {
+		flushed = append(flushed, len(ids))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewCursor 返回了意外错误: %v", err)
+	}
+	if len(flushed) != 2 || flushed[0] != 2 || flushed[1] != 3 {
+		t.Fatalf("分批结果不正确: %v", flushed)
+	}
+	if len(drv.queries) != 1 || drv.committed {
+		t.Fatalf("非PostgreSQL不应使用游标: %v", drv.queries)
+	}
+}
+
+func TestNewCursorStop(t *testing.T) {
+	drv := &cursorTestDriver{
+		dialect: dialect.MySQL,
+		batches: [][][]any{{{int64(1)}, {int64(2)}, {int64(3)}}},
+	}
+	ids := []int64{}
+	spec := newCursorTestSpec(&ids)
+	scan := spec.Scan
+	spec.Scan = func(rows dialect.Rows, selects []ScannerField) error {
+		if err := scan(rows, selects); err != nil {
+			return err
+		}
+		return ErrQueryStop
+	}
+	if err := NewCursor(context.Background(), drv, spec, 10, func() error { return nil }); err != nil {
+		t.Fatalf("ErrQueryStop 不应作为错误返回: %v", err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("停止后不应继续读取: %v", ids)
+	}
+	if err := NewCursor(context.Background(), drv, spec, 0, nil); err == nil {
+		t.Fatal("batchSize 为 0 时应返回错误")
+	}
+}
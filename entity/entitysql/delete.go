@@ -95,7 +95,12 @@ func (b *deleteBuilder) deleter(ctx context.Context) (*Deleter, error) {
 	deleter := NewDeleter(ctx)
 	// t := b.entityBuilder.builder.Table(b.Entity.Name)
 	deleter.SetDialect(b.builder.dialect)
-	deleter.SetEntity(b.Entity.Name)
+	deleter.SetSchema(schemaName(ctx, b.Entity.Schema)).SetEntity(b.Entity.Name)
+	tenant, err := newTenantScope(ctx, b.Entity, "")
+	if err != nil {
+		return nil, err
+	}
+	deleter.tenant = tenant
 	if pred := b.Predicate; pred != nil {
 		deleter.where = P(deleter.Builder)
 		pred(deleter.where)
@@ -0,0 +1,125 @@
+package entitysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// HistorySpec 查询实体历史记录的信息。
+type HistorySpec struct {
+	// Entity 实体表的信息，历史表和实体表在同一个模式中。
+	Entity EntitySpec
+	// EntityID 实体的主键，转换为字符串。
+	EntityID string
+}
+
+// NewHistorySpec 创建一个查询实体历史记录的信息。
+//
+// Params:
+//
+//   - entity: 实体表的名称。
+//   - id: 实体的主键，转换为字符串。
+func NewHistorySpec(entity string, id string) *HistorySpec {
+	return &HistorySpec{
+		Entity: EntitySpec{
+			Name: entity,
+		},
+		EntityID: id,
+	}
+}
+
+// NewHistory 查询实体的历史记录，按照操作的顺序返回。
+// 实体设置了租户字段时，只返回context中的租户的历史记录。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - spec: 查询实体历史记录的信息。
+//
+// Returns:
+//
+//	0: 历史记录。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+func NewHistory(ctx context.Context, drv dialect.Driver, spec *HistorySpec) ([]*entity.HistoryRecord, error) {
+	sqlSpec, err := spec.query(ctx, drv.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	logSql(&sqlSpec)
+	var rows dialect.Rows
+	if err := drv.Query(ctx, sqlSpec.Query, sqlSpec.Args, &rows); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := []*entity.HistoryRecord{}
+	for rows.Next() {
+		var (
+			r        entity.HistoryRecord
+			old, new []byte
+			actor    sql.NullString
+		)
+		if err := rows.Scan(&r.ID, &r.Operation, &r.EntityID, &old, &new, &actor, &r.ChangedAt); err != nil {
+			return nil, err
+		}
+		r.Old, r.New, r.Actor = old, new, actor.String
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// query 生成查询实体历史记录的语句，实体设置了租户字段时加上租户的过滤条件。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - d: 数据库方言。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+func (spec *HistorySpec) query(ctx context.Context, d dialect.DbDriver) (SqlSpec, error) {
+	tenant, err := newTenantScope(ctx, &spec.Entity, "")
+	if err != nil {
+		return SqlSpec{}, err
+	}
+	b := &Builder{dialect: d}
+	b.WriteString("SELECT ")
+	b.IdentComma("history_id", "operation", "entity_id", "old_row", "new_row", "actor", "changed_at")
+	b.WriteString(" FROM ")
+	b.WriteSchema(schemaName(ctx, spec.Entity.Schema)).Ident(spec.Entity.Name + entity.HistoryTableSuffix)
+	b.WriteString(" WHERE ")
+	if tenant != nil {
+		tenant.write(b)
+		b.WriteString(" AND ")
+	}
+	b.Ident("entity_id").WriteString(" = ").Arg(spec.EntityID)
+	b.WriteString(" ORDER BY ").Ident("history_id")
+	return SqlSpec{Query: b.String(), Args: b.args}, nil
+}
+
+// SetActor 把context中的操作人设置到事务的[entity.HistoryActorSetting]中，
+// 历史表的触发器从中读取操作人，设置只在当前事务中有效。context中没有操作人时不执行任何语句。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - tx: 事务。
+func SetActor(ctx context.Context, tx dialect.Tx) error {
+	actor, ok := entity.ActorFromContext(ctx)
+	if !ok || tx.Dialect() != dialect.PostgreSQL {
+		return nil
+	}
+	b := &Builder{dialect: tx.Dialect()}
+	b.WriteString("SELECT set_config(").Arg(entity.HistoryActorSetting).Comma().Arg(actor).WriteString(", true)")
+	sqlSpec := SqlSpec{Query: b.String(), Args: b.args}
+	logSql(&sqlSpec)
+	return tx.Exec(ctx, sqlSpec.Query, sqlSpec.Args, nil)
+}
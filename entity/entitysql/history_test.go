@@ -0,0 +1,82 @@
+package entitysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
+)
+
+func TestHistoryQuery(t *testing.T) {
+	spec := NewHistorySpec("users", "7")
+	spec.Entity.Schema = "app"
+	sqlSpec, err := spec.query(context.Background(), dialect.PostgreSQL)
+	want := `SELECT "history_id", "operation", "entity_id", "old_row", "new_row", "actor", "changed_at" ` +
+		`FROM "app"."users_history" WHERE "entity_id" = $1 ORDER BY "history_id"`
+	if err != nil || sqlSpec.Query != want || len(sqlSpec.Args) != 1 || sqlSpec.Args[0] != "7" {
+		t.Fatalf("查询历史记录的语句不正确: %s %v %v", sqlSpec.Query, sqlSpec.Args, err)
+	}
+}
+
+func TestHistoryTenant(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	spec := NewHistorySpec("users", "7")
+	spec.Entity.TenantField = "org_id"
+	_, err := NewHistory(context.Background(), drv, spec)
+	requireErrCode(t, err, entity.Err_0100030011)
+	if len(drv.Calls()) != 0 {
+		t.Fatal("context中没有租户时不应执行语句")
+	}
+	// 租户2查询租户1的实体时，数据库只返回租户2的历史记录，这里没有记录。
+	drv.ExpectQuery(`SELECT "history_id", "operation", "entity_id", "old_row", "new_row", "actor", "changed_at" `+
+		`FROM "users_history" WHERE "org_id" = $1 AND "entity_id" = $2 ORDER BY "history_id"`).
+		WithArgs(int64(2), "7").WillReturnRows(fake.NewRows("history_id"))
+	records, err := NewHistory(entity.WithTenant(context.Background(), int64(2)), drv, spec)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("其他租户的历史记录不应返回: %v %v", records, err)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewHistory(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	now := time.Now()
+	drv.ExpectQuery(`SELECT .* FROM "users_history" .*`).Regexp().WithArgs("7").WillReturnRows(
+		fake.NewRows("history_id", "operation", "entity_id", "old_row", "new_row", "actor", "changed_at").
+			AddRow(int64(1), "INSERT", "7", nil, []byte(`{"id": 7}`), "alice", now).
+			AddRow(int64(2), "DELETE", "7", []byte(`{"id": 7}`), nil, nil, now),
+	)
+	records, err := NewHistory(context.Background(), drv, NewHistorySpec("users", "7"))
+	if err != nil {
+		t.Fatalf("NewHistory 返回了意外错误: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("历史记录的数量不正确: %d", len(records))
+	}
+	if r := records[0]; r.Operation != entity.HistoryInsert || r.Old != nil || string(r.New) != `{"id": 7}` || r.Actor != "alice" {
+		t.Fatalf("新增的历史记录不正确: %+v", r)
+	}
+	if r := records[1]; r.Operation != entity.HistoryDelete || r.New != nil || r.Actor != "" || !r.ChangedAt.Equal(now) {
+		t.Fatalf("删除的历史记录不正确: %+v", r)
+	}
+}
+
+func TestSetActor(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	tx, _ := drv.Tx(context.Background())
+	if err := SetActor(context.Background(), tx); err != nil || len(drv.Calls()) != 0 {
+		t.Fatal("context中没有操作人时不应执行语句")
+	}
+	drv.ExpectExec(`SELECT set_config($1, $2, true)`).WithArgs(entity.HistoryActorSetting, "alice")
+	if err := SetActor(entity.WithActor(context.Background(), "alice"), tx); err != nil {
+		t.Fatalf("SetActor 返回了意外错误: %v", err)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
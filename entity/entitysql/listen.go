@@ -0,0 +1,110 @@
+package entitysql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+const (
+	// listenerMinReconnect 监听的连接断开后，第一次重新连接的间隔。
+	listenerMinReconnect = time.Second
+	// listenerMaxReconnect 监听的连接重新连接失败时，间隔会逐渐增加到这个值。
+	listenerMaxReconnect = time.Minute
+)
+
+// NewListener 创建一个接收通知的连接。驱动实现了[dialect.Notifier]时使用驱动创建，
+// 否则PostgreSQL通过驱动的连接字符串创建lib/pq的Listener，连接断开时会自动重新连接。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//
+// Returns:
+//
+//	0: 接收通知的连接。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100030013
+func NewListener(ctx context.Context, drv dialect.Driver) (dialect.Listener, error) {
+	if n, ok := drv.(dialect.Notifier); ok {
+		return n.Listener(ctx)
+	}
+	d, ok := drv.(interface{ DSN() string })
+	if !ok || d.DSN() == "" || drv.Dialect() != dialect.PostgreSQL {
+		return nil, entity.Err_0100030013.Sprintf(drv.Dialect())
+	}
+	return newPqListener(d.DSN()), nil
+}
+
+// pqListener 使用lib/pq的Listener实现的[dialect.Listener]。
+type pqListener struct {
+	l      *pq.Listener
+	notify chan *dialect.Notification
+	// done Close之后被关闭，剩余的通知会被丢弃。
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newPqListener 创建lib/pq的Listener，并转发收到的通知。
+//
+// Params:
+//
+//   - dsn: 连接字符串。
+func newPqListener(dsn string) *pqListener {
+	l := &pqListener{
+		l:      pq.NewListener(dsn, listenerMinReconnect, listenerMaxReconnect, nil),
+		notify: make(chan *dialect.Notification),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(l.notify)
+		for n := range l.l.Notify {
+			// 重新连接后lib/pq会发送nil。
+			var v *dialect.Notification
+			if n != nil {
+				v = &dialect.Notification{Channel: n.Channel, Payload: n.Extra}
+			}
+			select {
+			case l.notify <- v:
+			case <-l.done:
+			}
+		}
+	}()
+	return l
+}
+
+// Listen 实现了[dialect.Listener]，lib/pq在连接建立之前会一直等待，ctx结束时关闭连接。
+func (l *pqListener) Listen(ctx context.Context, channel string) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- l.l.Listen(channel)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		l.Close()
+		return ctx.Err()
+	}
+}
+
+// Notify 实现了[dialect.Listener]。
+func (l *pqListener) Notify() <-chan *dialect.Notification {
+	return l.notify
+}
+
+// Close 实现了[dialect.Listener]。
+func (l *pqListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return l.l.Close()
+}
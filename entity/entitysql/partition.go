@@ -0,0 +1,61 @@
+package entitysql
+
+import (
+	"context"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// PartitionSpec 创建分区的信息。
+type PartitionSpec struct {
+	// Entity 分区表的父表的信息。
+	Entity EntitySpec
+	// Bound 分区的名称和范围。
+	Bound entity.PartitionBound
+}
+
+// NewPartitionSpec 创建一个创建分区的信息。
+//
+// Params:
+//
+//   - entity: 分区表的父表的名称。
+//   - bound: 分区的名称和范围。
+func NewPartitionSpec(entity string, bound entity.PartitionBound) *PartitionSpec {
+	return &PartitionSpec{
+		Entity: EntitySpec{
+			Name: entity,
+		},
+		Bound: bound,
+	}
+}
+
+// NewPartition 生成创建分区的语句，并执行。分区已经存在时不会报错。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - spec: 创建分区的信息。
+func NewPartition(ctx context.Context, drv dialect.Driver, spec *PartitionSpec) error {
+	sqlSpec := spec.query(ctx, drv.Dialect())
+	logSql(&sqlSpec)
+	return drv.Exec(ctx, sqlSpec.Query, sqlSpec.Args, nil)
+}
+
+// query 生成创建分区的语句，分区和父表在同一个模式中。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - d: 数据库方言。
+func (spec *PartitionSpec) query(ctx context.Context, d dialect.DbDriver) SqlSpec {
+	schema := schemaName(ctx, spec.Entity.Schema)
+	b := &Builder{dialect: d}
+	b.WriteString("CREATE TABLE IF NOT EXISTS ")
+	b.WriteSchema(schema).Ident(spec.Bound.Name)
+	b.WriteString(" PARTITION OF ")
+	b.WriteSchema(schema).Ident(spec.Entity.Name)
+	b.Blank().WriteString(spec.Bound.SQL())
+	return SqlSpec{Query: b.String(), Args: b.args}
+}
@@ -0,0 +1,28 @@
+package entitysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+func TestPartitionQuery(t *testing.T) {
+	month := time.Date(2026, 12, 15, 8, 0, 0, 0, time.UTC)
+	spec := NewPartitionSpec("metric", entity.MonthPartition("metric", month))
+	spec.Entity.Schema = "ts"
+	query := spec.query(context.Background(), dialect.PostgreSQL).Query
+	want := `CREATE TABLE IF NOT EXISTS "ts"."metric_2026_12" PARTITION OF "ts"."metric" ` +
+		`FOR VALUES FROM ('2026-12-01 00:00:00+00:00') TO ('2027-01-01 00:00:00+00:00')`
+	if query != want {
+		t.Fatalf("创建分区的语句不正确: %s", query)
+	}
+
+	spec = NewPartitionSpec("metric", entity.DefaultPartition("metric_default"))
+	query = spec.query(NewSchemaContext(context.Background(), "tenant_a"), dialect.PostgreSQL).Query
+	if query != `CREATE TABLE IF NOT EXISTS "tenant_a"."metric_default" PARTITION OF "tenant_a"."metric" DEFAULT` {
+		t.Fatalf("创建默认分区的语句不正确: %s", query)
+	}
+}
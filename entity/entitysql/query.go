@@ -2,7 +2,9 @@ package entitysql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/zodileap/taurus_go/entity"
 	"github.com/zodileap/taurus_go/entity/dialect"
@@ -30,9 +32,13 @@ type (
 	}
 
 	RelationTable struct {
-		Table   string
-		Field   string
-		Columns []FieldName
+		Table string
+		// Schema 表所在的模式，为空时使用数据库默认的模式。
+		Schema string
+		// TenantField 表的租户字段，设置后联表时会加上租户的过滤条件。
+		TenantField string
+		Field       string
+		Columns     []FieldName
 	}
 
 	// Relation 用于生成联表查询。
@@ -48,8 +54,11 @@ func AddRelBySelector(s *Selector, t *SelectTable, desc RelationDesc) *SelectTab
 	var (
 		build = NewDialect(s.Dialect())
 	)
-	joinT := build.Table(desc.Join.Table).Schema(s.Table().schema)
+	joinT := build.Table(desc.Join.Table).Schema(schemaName(s.ctx, desc.Join.Schema))
 	s.LeftJoin(joinT).On(t.C(desc.To.Field), joinT.C(desc.Join.Field))
+	if desc.Join.TenantField != "" {
+		tenantJoin(s, joinT, desc.Join.TenantField)
+	}
 	s.SetSelect(joinT.as, s.Rows(NewFieldSpecs(desc.Join.Columns...)...)...)
 
 	if orders := desc.Orders; len(orders) > 0 {
@@ -117,6 +126,10 @@ type QuerySpec struct {
 	// Rels 用于生成联表查询。
 	Rels   []Relation
 	Orders []OrderFunc
+	// Cache 查询结果的缓存，为nil或者CacheTTL为0时不使用缓存。
+	Cache entity.QueryCache
+	// CacheTTL 缓存的过期时间。
+	CacheTTL time.Duration
 }
 
 // NewQuerySpec 创建一个QuerySpec。
@@ -138,11 +151,17 @@ func NewQuerySpec(entity string, rows []FieldName) *QuerySpec {
 	}
 }
 
+// ErrQueryStop Scan返回该错误时，停止读取剩余的行，查询本身不会返回错误。
+var ErrQueryStop = errors.New("entitysql: query stopped")
+
 // NewQuery 查询一个实体，并将返回的结果扫描到指定的值中。
 func NewQuery(ctx context.Context, drv dialect.Driver, spec *QuerySpec) error {
 	builder := NewDialect(drv.Dialect())
 	qb := queryBuilder{QuerySpec: spec, entityBuilder: entityBuilder{builder: builder}}
-	return qb.query(ctx, drv)
+	if err := qb.query(ctx, drv); err != nil && !errors.Is(err, ErrQueryStop) {
+		return err
+	}
+	return nil
 }
 
 // queryBuilder 查询语句生成器。
@@ -171,23 +190,48 @@ func (b *queryBuilder) query(ctx context.Context, drv dialect.Driver) error {
 		tlog.Debug(*config.SqlLogger, fmt.Sprintf("sql: %s", spec.Query))
 		tlog.Debug(*config.SqlLogger, fmt.Sprintf("args: %v", spec.Args))
 	}
+	if b.Cache != nil && b.CacheTTL > 0 {
+		return b.cachedQuery(ctx, drv, selector, spec)
+	}
 	var rows dialect.Rows
 	err = drv.Query(ctx, spec.Query, spec.Args, &rows)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
+	if _, err := b.scanRows(rows); err != nil {
+		return err
+	}
+	return nil
+}
+
+// scanRows 逐行调用Scan，Scan返回ErrQueryStop时停止读取。
+//
+// Params:
+//
+//   - rows: 查询结果。
+//
+// Returns:
+//
+//	0: 读取的行数。
+//	1: 错误信息。
+func (b *queryBuilder) scanRows(rows dialect.Rows) (int, error) {
+	n := 0
+	ScannerFields := make([]ScannerField, len(b.Entity.Columns))
+	for i, c := range b.Entity.Columns {
+		ScannerFields[i] = c.Name
+	}
 	for rows.Next() {
-		ScannerFields := make([]ScannerField, len(b.Entity.Columns))
-		for i, c := range b.Entity.Columns {
-			ScannerFields[i] = c.Name
-		}
+		n++
 		err := b.Scan(rows, ScannerFields)
+		if errors.Is(err, ErrQueryStop) {
+			return n, ErrQueryStop
+		}
 		if err != nil {
-			return err
+			return n, err
 		}
 	}
-
-	return nil
+	return n, rows.Err()
 }
 
 // selector 生成查询语句。
@@ -201,7 +245,7 @@ func (b *queryBuilder) query(ctx context.Context, drv dialect.Driver) error {
 //	0: 选择语句生成器。
 func (b *queryBuilder) selector(ctx context.Context) (*Selector, error) {
 	selector := b.builder.Select()
-	t := b.builder.Table(b.Entity.Name)
+	t := b.builder.Table(b.Entity.Name).Schema(schemaName(ctx, b.Entity.Schema))
 	selector.SetFrom(t)
 	selector.SetSelect(t.as, selector.Rows(b.Entity.Columns...)...)
 	selector.SetContext(ctx)
@@ -213,6 +257,11 @@ func (b *queryBuilder) selector(ctx context.Context) (*Selector, error) {
 	if pred := b.Predicate; pred != nil {
 		pred(selector.where)
 	}
+	tenant, err := newTenantScope(ctx, b.Entity, t.as)
+	if err != nil {
+		return nil, err
+	}
+	selector.tenant = tenant
 	if orders := b.Orders; len(orders) > 0 {
 		for _, order := range orders {
 			o := O()
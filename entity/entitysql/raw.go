@@ -0,0 +1,178 @@
+package entitysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/stringutil"
+)
+
+// RawSpec 原生SQL查询的信息。
+type RawSpec struct {
+	SqlSpec
+	// Scan 每一行结果都会调用一次，selects为结果集中的列名。
+	Scan Scanner
+}
+
+// NewRawSpec 创建一个原生SQL查询的信息。
+//
+// Params:
+//
+//   - query: 原生SQL语句。
+//   - args: SQL语句的参数。
+func NewRawSpec(query string, args ...any) *RawSpec {
+	return &RawSpec{
+		SqlSpec: SqlSpec{
+			Query: query,
+			Args:  args,
+		},
+	}
+}
+
+// NewRaw 执行原生SQL查询，并按结果集中的列名逐行调用Scan。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接或事务。
+//   - spec: 原生SQL查询的信息。
+func NewRaw(ctx context.Context, drv dialect.ExecQuerier, spec *RawSpec) error {
+	logSql(&spec.SqlSpec)
+	var rows dialect.Rows
+	if err := drv.Query(ctx, spec.Query, spec.Args, &rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields := make([]ScannerField, len(columns))
+	for i, c := range columns {
+		fields[i] = FieldName(c)
+	}
+	for rows.Next() {
+		if err := spec.Scan(rows, fields); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Discard 将扫描目标中为nil的位置替换为丢弃值，
+// 用于结果集中包含实体没有的列时，忽略这些列。
+//
+// Params:
+//
+//   - args: 扫描目标。
+//
+// Returns:
+//
+//	0: 替换后的扫描目标。
+func Discard(args []any) []any {
+	for i := range args {
+		if args[i] == nil {
+			args[i] = new(any)
+		}
+	}
+	return args
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// ScanInto 执行原生SQL查询，并将结果扫描到T中。
+// T为结构体时，按字段的`db`标签匹配列名，没有标签时使用字段名的蛇形命名，
+// `db:"-"`的字段和结构体中没有的列会被忽略；T不是结构体时，结果集只能有一列。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接或事务。
+//   - query: 原生SQL语句。
+//   - args: SQL语句的参数。
+//
+// Returns:
+//
+//	0: 扫描的结果。
+//	1: 错误信息。
+//
+// Example:
+//
+//	type report struct {
+//		UserID int64 `db:"user_id"`
+//		Total  int64
+//	}
+//	rs, err := entitysql.ScanInto[report](ctx, db.Driver, "SELECT user_id, count(*) AS total FROM blog GROUP BY user_id")
+func ScanInto[T any](ctx context.Context, drv dialect.ExecQuerier, query string, args ...any) ([]T, error) {
+	res := []T{}
+	spec := NewRawSpec(query, args...)
+	spec.Scan = func(rows dialect.Rows, selects []ScannerField) error {
+		var v T
+		dest, err := scanDest(reflect.ValueOf(&v).Elem(), selects)
+		if err != nil {
+			return err
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		res = append(res, v)
+		return nil
+	}
+	if err := NewRaw(ctx, drv, spec); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// scanDest 根据列名获取v中对应的扫描目标。
+func scanDest(v reflect.Value, selects []ScannerField) ([]any, error) {
+	if v.Kind() != reflect.Struct || v.Type() == timeType || v.Addr().Type().Implements(scannerType) {
+		if len(selects) != 1 {
+			return nil, fmt.Errorf("entitysql: scan %d columns into non-struct type %s", len(selects), v.Type())
+		}
+		return []any{v.Addr().Interface()}, nil
+	}
+	columns := map[string]reflect.Value{}
+	structColumns(v, columns)
+	dest := make([]any, len(selects))
+	for i, s := range selects {
+		if f, ok := columns[s.String()]; ok {
+			dest[i] = f.Addr().Interface()
+		}
+	}
+	return Discard(dest), nil
+}
+
+// structColumns 收集结构体中列名与字段的对应关系，匿名嵌入的结构体会被展开。
+func structColumns(v reflect.Value, columns map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			structColumns(v.Field(i), columns)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = stringutil.ToSnakeCase(f.Name)
+		}
+		if _, ok := columns[name]; !ok {
+			columns[name] = v.Field(i)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package entitysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+type rawTestQuerier struct {
+	columns []string
+	values  [][]any
+	query   string
+	args    []any
+}
+
+func (q *rawTestQuerier) Exec(ctx context.Context, query string, args []any, v any) error {
+	return nil
+}
+
+func (q *rawTestQuerier) Query(ctx context.Context, query string, args []any, v *dialect.Rows) error {
+	q.query = query
+	q.args = args
+	v.RowsScanner = &rawTestRows{columns: q.columns, values: q.values, index: -1}
+	return nil
+}
+
+type rawTestRows struct {
+	columns []string
+	values  [][]any
+	index   int
+	closed  bool
+}
+
+func (r *rawTestRows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *rawTestRows) ColumnTypes() ([]*sql.ColumnType, error) {
+	return nil, nil
+}
+
+func (r *rawTestRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func (r *rawTestRows) Err() error {
+	return nil
+}
+
+func (r *rawTestRows) Next() bool {
+	r.index++
+	return r.index < len(r.values)
+}
+
+func (r *rawTestRows) NextResultSet() bool {
+	return false
+}
+
+func (r *rawTestRows) Scan(dest ...any) error {
+	row := r.values[r.index]
+	if len(dest) != len(row) {
+		return fmt.Errorf("扫描目标数量 %d 与列数 %d 不一致", len(dest), len(row))
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(row[i]))
+	}
+	return nil
+}
+
+type rawTestBase struct {
+	ID int64
+}
+
+type rawTestReport struct {
+	rawTestBase
+	UserName  string `db:"name"`
+	BlogTotal int64
+	Ignored   string `db:"-"`
+}
+
+func TestScanIntoStructMatchesColumns(t *testing.T) {
+	q := &rawTestQuerier{
+		columns: []string{"id", "name", "blog_total", "rank"},
+		values: [][]any{
+			{int64(1), "a", int64(3), int64(1)},
+			{int64(2), "b", int64(5), int64(2)},
+		},
+	}
+	rs, err := ScanInto[rawTestReport](context.Background(), q, "SELECT * FROM report WHERE id > $1", 0)
+	if err != nil {
+		t.Fatalf("ScanInto 返回了意外错误: %v", err)
+	}
+	want := []rawTestReport{
+		{rawTestBase: rawTestBase{ID: 1}, UserName: "a", BlogTotal: 3},
+		{rawTestBase: rawTestBase{ID: 2}, UserName: "b", BlogTotal: 5},
+	}
+	if !reflect.DeepEqual(rs, want) {
+		t.Fatalf("扫描结果不正确: %#v", rs)
+	}
+	if q.query != "SELECT * FROM report WHERE id > $1" || !reflect.DeepEqual(q.args, []any{0}) {
+		t.Fatalf("执行的SQL不正确: %s %v", q.query, q.args)
+	}
+}
+
+func TestScanIntoSingleColumn(t *testing.T) {
+	q := &rawTestQuerier{
+		columns: []string{"count"},
+		values:  [][]any{{int64(7)}},
+	}
+	rs, err := ScanInto[int64](context.Background(), q, "SELECT count(*) FROM blog")
+	if err != nil {
+		t.Fatalf("ScanInto 返回了意外错误: %v", err)
+	}
+	if len(rs) != 1 || rs[0] != 7 {
+		t.Fatalf("扫描结果不正确: %v", rs)
+	}
+
+	q.columns = []string{"a", "b"}
+	q.values = [][]any{{int64(1), int64(2)}}
+	if _, err := ScanInto[int64](context.Background(), q, "SELECT 1, 2"); err == nil {
+		t.Fatal("多列扫描到非结构体时应返回错误")
+	}
+}
+
+func TestNewRawPassesColumnNames(t *testing.T) {
+	q := &rawTestQuerier{
+		columns: []string{"id", "total"},
+		values:  [][]any{{int64(1), int64(2)}},
+	}
+	spec := NewRawSpec("SELECT id, total FROM report")
+	spec.Scan = func(rows dialect.Rows, selects []ScannerField) error {
+		if len(selects) != 2 || selects[0].String() != "id" || selects[1].String() != "total" {
+			t.Fatalf("列名不正确: %#v", selects)
+		}
+		return rows.Scan(Discard(make([]any, len(selects)))...)
+	}
+	if err := NewRaw(context.Background(), q, spec); err != nil {
+		t.Fatalf("NewRaw 返回了意外错误: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package entitysql
+
+import "context"
+
+// SchemaContextKey 用于在context中存储运行时指定的模式。
+type SchemaContextKey struct{}
+
+// NewSchemaContext 将模式添加到context中，并返回一个新的context。
+// 使用这个context执行的查询、新增、更新和删除，所有实体表都会使用这个模式，
+// 忽略生成代码中的模式，用于每个租户一个模式的部署。
+//
+// Params:
+//
+//   - parent: 父context。
+//   - schema: 模式名称。
+//
+// Returns:
+//
+//	0: 新的context。
+func NewSchemaContext(parent context.Context, schema string) context.Context {
+	return context.WithValue(parent, SchemaContextKey{}, schema)
+}
+
+// SchemaFromContext 从context中获取运行时指定的模式。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//
+// Returns:
+//
+//	0: 模式名称，没有指定时为空字符串。
+func SchemaFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	s, _ := ctx.Value(SchemaContextKey{}).(string)
+	return s
+}
+
+// schemaName 返回实体表实际使用的模式，context中指定的模式优先。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - schema: 实体表的模式。
+func schemaName(ctx context.Context, schema string) string {
+	if s := SchemaFromContext(ctx); s != "" {
+		return s
+	}
+	return schema
+}
@@ -0,0 +1,78 @@
+package entitysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+func newSchemaTestQuery(ctx context.Context, t *testing.T, spec *QuerySpec) string {
+	qb := queryBuilder{QuerySpec: spec, entityBuilder: entityBuilder{builder: NewDialect(dialect.PostgreSQL)}}
+	selector, err := qb.selector(ctx)
+	if err != nil {
+		t.Fatalf("selector 返回了意外错误: %v", err)
+	}
+	sqlSpec, err := selector.Query()
+	if err != nil {
+		t.Fatalf("Query 返回了意外错误: %v", err)
+	}
+	return sqlSpec.Query
+}
+
+func TestSchemaQualifiedQuery(t *testing.T) {
+	spec := NewQuerySpec("users", []FieldName{"id"})
+	spec.Entity.Schema = "shop"
+	query := newSchemaTestQuery(context.Background(), t, spec)
+	if !strings.Contains(query, `FROM "shop"."users"`) {
+		t.Fatalf("查询语句缺少模式: %s", query)
+	}
+
+	ctx := NewSchemaContext(context.Background(), "tenant_a")
+	query = newSchemaTestQuery(ctx, t, spec)
+	if !strings.Contains(query, `FROM "tenant_a"."users"`) {
+		t.Fatalf("context中的模式没有生效: %s", query)
+	}
+
+	spec.Entity.Schema = ""
+	query = newSchemaTestQuery(context.Background(), t, spec)
+	if strings.Contains(query, `"."users"`) {
+		t.Fatalf("没有设置模式时不应带上模式: %s", query)
+	}
+}
+
+func TestSchemaQualifiedJoin(t *testing.T) {
+	spec := NewQuerySpec("blog", []FieldName{"id", "user_id"})
+	spec.Entity.Schema = "shop"
+	spec.Rels = []Relation{func(s *Selector) {
+		AddRelBySelector(s, s.Table(), RelationDesc{
+			To:   RelationTable{Table: "blog", Schema: "shop", Field: "user_id"},
+			Join: RelationTable{Table: "user_info", Schema: "account", Field: "id", Columns: []FieldName{"id"}},
+		})
+	}}
+	query := newSchemaTestQuery(context.Background(), t, spec)
+	if !strings.Contains(query, `JOIN "account"."user_info"`) {
+		t.Fatalf("联表查询缺少模式: %s", query)
+	}
+}
+
+func TestSchemaQualifiedDelete(t *testing.T) {
+	spec := NewDeleteSpec("users")
+	spec.Entity.Schema = "shop"
+	spec.Predicate = func(p *Predicate) {
+		p.EQ("id", "", 1)
+	}
+	db := deleteBuilder{DeleteSpec: spec, entityBuilder: entityBuilder{builder: NewDialect(dialect.PostgreSQL)}}
+	deleter, err := db.deleter(NewSchemaContext(context.Background(), "tenant_a"))
+	if err != nil {
+		t.Fatalf("deleter 返回了意外错误: %v", err)
+	}
+	specs, err := deleter.Query()
+	if err != nil {
+		t.Fatalf("Query 返回了意外错误: %v", err)
+	}
+	if len(specs) != 1 || !strings.HasPrefix(specs[0].Query, `DELETE FROM "tenant_a"."users"`) {
+		t.Fatalf("删除语句的模式不正确: %v", specs)
+	}
+}
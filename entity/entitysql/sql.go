@@ -56,6 +56,10 @@ type (
 	EntitySpec struct {
 		// Name 实体表的名称，和[entity.EntityConfig]中的AttrName相同。
 		Name string
+		// Schema 实体表所在的模式，为空时使用数据库默认的模式。
+		Schema string
+		// TenantField 租户字段的名称，设置后会根据context中的租户过滤数据。
+		TenantField string
 		// Columns 实体表的列名，通过这个属性会指定Select中包含的列。
 		Columns []FieldSpec
 	}
@@ -295,6 +299,10 @@ type (
 		order        []*Order
 		joins        []join
 		table        *SelectTable
+		// tenant 租户的过滤条件。
+		tenant *tenantScope
+		// err 生成语句时的错误，在Query中返回。
+		err error
 	}
 	// Selection 选择的字段。
 	Selection struct {
@@ -314,6 +322,9 @@ func (s Selection) String() string {
 //	0: 查询语句。
 //	1: 查询参数。
 func (s *Selector) Query() (SqlSpec, error) {
+	if s.err != nil {
+		return SqlSpec{}, s.err
+	}
 	if len(s.from)+len(s.joins) > 1 {
 		s.Builder.IsAs = true
 	}
@@ -365,10 +376,7 @@ func (s *Selector) Query() (SqlSpec, error) {
 			b.Join(join.on)
 		}
 	}
-	if s.where != nil && len(s.where.fns) > 0 {
-		b.WriteString(" WHERE ")
-		b.Join(s.where)
-	}
+	s.tenant.where(b, s.where)
 	batchSize := *(entity.GetConfig().BatchSize)
 	if len(b.args) > batchSize {
 		return SqlSpec{}, entity.Err_0100030004
@@ -404,6 +412,8 @@ func (s *Selector) Clone() *Selector {
 		from:    s.from,
 		limit:   s.limit,
 		where:   s.where.clone(),
+		tenant:  s.tenant,
+		err:     s.err,
 		joins:   append([]join{}, joins...),
 		order:   append([]*Order{}, s.order...),
 	}
@@ -705,19 +715,24 @@ func (i *Inserter) Insert() ([]SqlSpec, error) {
 	specs := []SqlSpec{}
 	current := 0
 	b := i.Builder.new()
-	if i.defaults && len(i.columns) == 0 {
+	columns := i.valueColumns()
+	if (i.defaults && len(i.columns) == 0) || (len(columns) == 0 && i.rowTotal == 1) {
+		i.setInitialQuery(b)
 		i.writeDefault(b)
 	} else {
+		if len(columns) == 0 {
+			columns = i.columns
+		}
 		i.setInitialQuery(b)
-		b.WriteByte('(').IdentComma(i.columns...).WriteByte(')')
+		b.WriteByte('(').IdentComma(columns...).WriteByte(')')
 		b.WriteString(" VALUES ")
 		batchSize := *(entity.GetConfig().BatchSize)
 		for j := 0; j < i.rowTotal; j++ {
-			if current+len(i.columns) > batchSize {
+			if current+len(columns) > batchSize {
 				specs = append(specs, SqlSpec{Query: b.String(), Args: b.args})
 				b = i.Builder.new()
 				i.setInitialQuery(b)
-				b.WriteByte('(').IdentComma(i.columns...).WriteByte(')')
+				b.WriteByte('(').IdentComma(columns...).WriteByte(')')
 				b.WriteString(" VALUES ")
 				current = 0
 			}
@@ -725,11 +740,11 @@ func (i *Inserter) Insert() ([]SqlSpec, error) {
 				b.Comma()
 			}
 			v := []any{}
-			for _, column := range i.columns {
+			for _, column := range columns {
 				v = append(v, i.values[column][j])
 			}
 			b.WriteByte('(').Args(v...).WriteByte(')')
-			current += len(i.columns)
+			current += len(columns)
 		}
 	}
 	// if i.conflict != nil {
@@ -741,6 +756,21 @@ func (i *Inserter) Insert() ([]SqlSpec, error) {
 	return specs, nil
 }
 
+// valueColumns 返回至少有一行设置了值的列，所有行都使用默认值的列不会出现在插入语句中，
+// 例如生成列、标识列和使用SQL表达式作为默认值的列，这些列的值由数据库生成，通过RETURNING读取。
+func (i *Inserter) valueColumns() []string {
+	columns := make([]string, 0, len(i.columns))
+	for _, column := range i.columns {
+		for _, v := range i.values[column] {
+			if v != IdentDefault {
+				columns = append(columns, column)
+				break
+			}
+		}
+	}
+	return columns
+}
+
 // setInitialQuery 设置初始的插入语句。
 //
 // Params:
@@ -783,6 +813,9 @@ func (i *Inserter) AddRow() *Inserter {
 //
 //	0: 插入语句生成器。
 func (i *Inserter) FillDefault() *Inserter {
+	if i.values == nil {
+		i.values = make(map[string][]any)
+	}
 	for _, column := range i.columns {
 		if _, ok := i.values[column]; !ok {
 			i.values[column] = make([]any, 0, i.rowTotal)
@@ -960,6 +993,8 @@ type (
 		returning []FieldName
 		// batchNum 批量更新的数量，这个用于防止参数过多。
 		batchNum int
+		// tenant 租户的过滤条件。
+		tenant *tenantScope
 	}
 )
 
@@ -994,11 +1029,7 @@ func (u *Updater) Query() ([]SqlSpec, error) {
 		b.WriteSchema(u.schema)
 		b.Ident(u.entity).WriteString(" SET ")
 		u.writeSetter(b, i)
-		if u.wheres[i] != nil {
-			b.WriteString(" WHERE ")
-			w := u.wheres[i]
-			b.Join(w)
-		}
+		u.tenant.where(b, u.wheres[i])
 		joinReturning(b, u.returning)
 		specs = append(specs, SqlSpec{Query: b.String(), Args: b.args})
 	}
@@ -1050,6 +1081,20 @@ func (u *Updater) SetEntity(entity string) *Updater {
 	return u
 }
 
+// SetSchema 设置模式名称。
+//
+// Params:
+//
+//   - schema: 模式名称。
+//
+// Returns:
+//
+//	0: 更新语句生成器。
+func (u *Updater) SetSchema(schema string) *Updater {
+	u.schema = schema
+	return u
+}
+
 // SetDialect 设置数据库方言。
 //
 // Params:
@@ -1095,6 +1140,8 @@ type (
 		entity string
 		// where 删除的条件。
 		where *Predicate
+		// tenant 租户的过滤条件。
+		tenant *tenantScope
 	}
 )
 
@@ -1129,12 +1176,13 @@ func (d *Deleter) Query() ([]SqlSpec, error) {
 		batchSize := *(entity.GetConfig().BatchSize)
 		batchNum := length/batchSize + 1
 		for i := 0; i < batchNum; i++ {
-			b.WriteString(" WHERE ")
-			if length < (i+1)*batchSize {
-				b.Join(d.where.Clone(i*batchSize, length))
-			} else {
-				b.Join(d.where.Clone(i*batchSize, (i+1)*batchSize))
+			end := min(length, (i+1)*batchSize)
+			if i*batchSize >= end {
+				// 条件的数量刚好是batchSize的倍数时，最后一批没有条件，
+				// 不能生成没有条件的删除语句。
+				break
 			}
+			d.tenant.where(b, d.where.Clone(i*batchSize, end))
 			specs = append(specs, SqlSpec{Query: b.String(), Args: b.args})
 			b = d.Builder.new()
 			d.setInitialQuery(b)
@@ -0,0 +1,108 @@
+package entitysql
+
+import (
+	"context"
+
+	"github.com/zodileap/taurus_go/entity"
+)
+
+// tenantScope 租户的过滤条件，会加到Where子句的最前面。
+type tenantScope struct {
+	// column 租户字段的名称。
+	column string
+	// as 表的别名。
+	as string
+	// value 租户。
+	value any
+}
+
+// newTenantScope 根据实体的租户字段，从context中获取租户，生成租户的过滤条件。
+// 实体没有设置租户字段时返回nil。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - spec: 实体表的信息。
+//   - as: 表的别名。
+//
+// Returns:
+//
+//	0: 租户的过滤条件。
+//	1: context中没有租户时返回错误。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+func newTenantScope(ctx context.Context, spec *EntitySpec, as string) (*tenantScope, error) {
+	if spec.TenantField == "" {
+		return nil, nil
+	}
+	tenant, ok := entity.TenantFromContext(ctx)
+	if !ok {
+		return nil, entity.Err_0100030011.Sprintf(spec.Name)
+	}
+	return &tenantScope{column: spec.TenantField, as: as, value: tenant}, nil
+}
+
+// where 写入Where子句。设置了租户时，写入租户的过滤条件，原来的条件用括号包裹后用AND连接。
+//
+// Params:
+//
+//   - b: sql生成器。
+//   - w: 原来的条件，可以为nil。
+func (t *tenantScope) where(b *Builder, w *Predicate) {
+	hasWhere := w != nil && len(w.fns) > 0
+	if t == nil {
+		if hasWhere {
+			b.WriteString(" WHERE ")
+			b.Join(w)
+		}
+		return
+	}
+	b.WriteString(" WHERE ")
+	t.write(b)
+	if hasWhere {
+		b.WriteString(" AND ")
+		b.Wrap(func(b *Builder) {
+			b.Join(w)
+		})
+	}
+}
+
+// write 写入租户字段等于租户的条件。
+//
+// Params:
+//
+//   - b: sql生成器。
+func (t *tenantScope) write(b *Builder) {
+	if b.IsAs && t.as != "" {
+		b.WriteString(b.Quote(t.as))
+		b.WriteByte('.')
+	}
+	b.Ident(t.column).WriteOp(OpEQ).Arg(t.value)
+}
+
+// tenantJoin 联表查询时，被联的表设置了租户字段，在ON中加上租户的过滤条件。
+// context中没有租户时，和查询实体表一样，生成语句时返回错误。
+//
+// Params:
+//
+//   - s: 选择语句生成器。
+//   - t: 被联的表。
+//   - column: 租户字段的名称。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+func tenantJoin(s *Selector, t *SelectTable, column string) {
+	tenant, ok := entity.TenantFromContext(s.ctx)
+	if !ok {
+		if s.err == nil {
+			s.err = entity.Err_0100030011.Sprintf(t.name)
+		}
+		return
+	}
+	s.OnP(P(s.Builder, func(b *Builder) {
+		b.WriteString(t.C(column)).WriteOp(OpEQ).Arg(tenant)
+	}))
+}
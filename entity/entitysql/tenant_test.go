@@ -0,0 +1,90 @@
+package entitysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	terr "github.com/zodileap/taurus_go/err"
+)
+
+func requireErrCode(t *testing.T, got error, want terr.ErrCode) {
+	t.Helper()
+	var errCode terr.ErrCode
+	if !errors.As(got, &errCode) || errCode.Code() != want.Code() {
+		t.Fatalf("期望错误码 %s，实际为 %v", want.Code(), got)
+	}
+}
+
+func TestTenantScopedQuery(t *testing.T) {
+	spec := NewQuerySpec("users", []FieldName{"id"})
+	spec.Entity.TenantField = "tenant_id"
+	spec.Predicate = func(p *Predicate) {
+		p.EQ("id", "", 1).Or().EQ("id", "", 2)
+	}
+	qb := queryBuilder{QuerySpec: spec, entityBuilder: entityBuilder{builder: NewDialect(dialect.PostgreSQL)}}
+	_, err := qb.selector(context.Background())
+	requireErrCode(t, err, entity.Err_0100030011)
+
+	selector, err := qb.selector(entity.WithTenant(context.Background(), int64(7)))
+	if err != nil {
+		t.Fatalf("selector 返回了意外错误: %v", err)
+	}
+	sqlSpec, err := selector.Query()
+	if err != nil {
+		t.Fatalf("Query 返回了意外错误: %v", err)
+	}
+	if !strings.Contains(sqlSpec.Query, `WHERE "tenant_id" = $1 AND ("id" = $2  OR "id" = $3 )`) {
+		t.Fatalf("查询语句缺少租户条件: %s", sqlSpec.Query)
+	}
+	if !reflect.DeepEqual(sqlSpec.Args, []any{int64(7), 1, 2}) {
+		t.Fatalf("查询参数不正确: %v", sqlSpec.Args)
+	}
+}
+
+func TestTenantScopedJoin(t *testing.T) {
+	spec := NewQuerySpec("blog", []FieldName{"id", "user_id"})
+	spec.Rels = []Relation{func(s *Selector) {
+		AddRelBySelector(s, s.Table(), RelationDesc{
+			To:   RelationTable{Table: "blog", Field: "user_id"},
+			Join: RelationTable{Table: "user_info", TenantField: "tenant_id", Field: "id", Columns: []FieldName{"id"}},
+		})
+	}}
+	query := newSchemaTestQuery(entity.WithTenant(context.Background(), "a"), t, spec)
+	if !strings.Contains(query, `"t2"."tenant_id" = $1`) {
+		t.Fatalf("联表缺少租户条件: %s", query)
+	}
+	qb := queryBuilder{QuerySpec: spec, entityBuilder: entityBuilder{builder: NewDialect(dialect.PostgreSQL)}}
+	selector, err := qb.selector(context.Background())
+	if err != nil {
+		t.Fatalf("selector 返回了意外错误: %v", err)
+	}
+	_, err = selector.Query()
+	requireErrCode(t, err, entity.Err_0100030011)
+}
+
+func TestTenantScopedDelete(t *testing.T) {
+	spec := NewDeleteSpec("users")
+	spec.Entity.TenantField = "tenant_id"
+	spec.Predicate = func(p *Predicate) {
+		p.EQ("id", "", 1)
+	}
+	db := deleteBuilder{DeleteSpec: spec, entityBuilder: entityBuilder{builder: NewDialect(dialect.PostgreSQL)}}
+	_, err := db.deleter(context.Background())
+	requireErrCode(t, err, entity.Err_0100030011)
+	deleter, err := db.deleter(entity.WithTenant(context.Background(), int64(7)))
+	if err != nil {
+		t.Fatalf("deleter 返回了意外错误: %v", err)
+	}
+	specs, err := deleter.Query()
+	if err != nil {
+		t.Fatalf("Query 返回了意外错误: %v", err)
+	}
+	if len(specs) != 1 || !strings.HasSuffix(specs[0].Query, `WHERE "tenant_id" = $1 AND ("id" = $2 )`) {
+		t.Fatalf("删除语句缺少租户条件: %v", specs)
+	}
+}
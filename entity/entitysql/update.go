@@ -104,7 +104,12 @@ func (b *updateBuilder) updater(ctx context.Context) (*Updater, error) {
 	updater := NewUpdater(ctx)
 	// t := b.entityBuilder.builder.Table(b.Entity.Name)
 	updater.SetDialect(b.builder.dialect)
-	updater.SetEntity(b.Entity.Name)
+	updater.SetSchema(schemaName(ctx, b.Entity.Schema)).SetEntity(b.Entity.Name)
+	tenant, err := newTenantScope(ctx, b.Entity, "")
+	if err != nil {
+		return nil, err
+	}
+	updater.tenant = tenant
 	for row, cs := range b.Sets {
 		updater.AddBatch()
 		for column, c := range cs {
@@ -0,0 +1,64 @@
+package entitysql
+
+import (
+	"context"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// RefreshSpec 刷新物化视图的信息。
+type RefreshSpec struct {
+	// Entity 物化视图的信息。
+	Entity EntitySpec
+	// Concurrently 是否使用CONCURRENTLY刷新，刷新时不会阻塞对视图的查询，但视图需要有唯一索引。
+	Concurrently bool
+	// Cache 查询缓存，刷新之后清除使用了物化视图的缓存，为nil时不清除。
+	Cache entity.QueryCache
+}
+
+// NewRefreshSpec 创建一个刷新物化视图的信息。
+//
+// Params:
+//
+//   - entity: 物化视图的名称。
+func NewRefreshSpec(entity string) *RefreshSpec {
+	return &RefreshSpec{
+		Entity: EntitySpec{
+			Name: entity,
+		},
+	}
+}
+
+// NewRefresh 生成刷新物化视图的语句，并执行，刷新之后清除使用了物化视图的缓存。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - spec: 刷新物化视图的信息。
+func NewRefresh(ctx context.Context, drv dialect.Driver, spec *RefreshSpec) error {
+	sqlSpec := spec.query(ctx, drv.Dialect())
+	logSql(&sqlSpec)
+	if err := drv.Exec(ctx, sqlSpec.Query, sqlSpec.Args, nil); err != nil {
+		return err
+	}
+	InvalidateCache(ctx, spec.Cache, CacheTable(ctx, spec.Entity.Schema, spec.Entity.Name))
+	return nil
+}
+
+// query 生成刷新物化视图的语句。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - d: 数据库方言。
+func (spec *RefreshSpec) query(ctx context.Context, d dialect.DbDriver) SqlSpec {
+	b := &Builder{dialect: d}
+	b.WriteString("REFRESH MATERIALIZED VIEW ")
+	if spec.Concurrently {
+		b.WriteString("CONCURRENTLY ")
+	}
+	b.WriteSchema(schemaName(ctx, spec.Entity.Schema)).Ident(spec.Entity.Name)
+	return SqlSpec{Query: b.String(), Args: b.args}
+}
@@ -0,0 +1,49 @@
+package entitysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
+)
+
+func TestRefreshQuery(t *testing.T) {
+	spec := NewRefreshSpec("user_stats")
+	spec.Entity.Schema = "report"
+	query := spec.query(context.Background(), dialect.PostgreSQL).Query
+	if query != `REFRESH MATERIALIZED VIEW "report"."user_stats"` {
+		t.Fatalf("刷新物化视图的语句不正确: %s", query)
+	}
+
+	spec.Concurrently = true
+	ctx := NewSchemaContext(context.Background(), "tenant_a")
+	query = spec.query(ctx, dialect.PostgreSQL).Query
+	if query != `REFRESH MATERIALIZED VIEW CONCURRENTLY "tenant_a"."user_stats"` {
+		t.Fatalf("并发刷新物化视图的语句不正确: %s", query)
+	}
+}
+
+func TestNewRefreshCache(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	c := &mapCache{values: map[string][]byte{}}
+	spec := NewRefreshSpec("user_stats")
+	spec.Entity.Schema = "report"
+	spec.Cache = c
+	drv.ExpectExec(`REFRESH MATERIALIZED VIEW "report"."user_stats"`).WillReturnError(errors.New("refresh failed"))
+	if err := NewRefresh(context.Background(), drv, spec); err == nil || len(c.invalidated) != 0 {
+		t.Fatalf("刷新失败时不应清除缓存: %v %v", err, c.invalidated)
+	}
+	drv.ExpectExec(`REFRESH MATERIALIZED VIEW "report"."user_stats"`)
+	if err := NewRefresh(context.Background(), drv, spec); err != nil {
+		t.Fatalf("NewRefresh 返回了意外错误: %v", err)
+	}
+	if !reflect.DeepEqual(c.invalidated, []string{"report.user_stats"}) {
+		t.Fatalf("刷新之后应清除物化视图的缓存: %v", c.invalidated)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
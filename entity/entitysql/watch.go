@@ -0,0 +1,282 @@
+package entitysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+)
+
+// watchPayloadLimit NOTIFY的内容需要小于8000字节，超过时只发送主键。
+const watchPayloadLimit = 7999
+
+type (
+	// WatchSpec 监听实体表变化的信息。
+	WatchSpec struct {
+		// Entity 实体表的信息，设置了租户字段时只接收context中的租户的变化。
+		Entity EntitySpec
+		// Primaries 主键的列名，行超过NOTIFY的长度限制时只发送主键。
+		Primaries []FieldName
+	}
+
+	// WatchEvent 通过NOTIFY收到的行的变化。
+	WatchEvent struct {
+		// Op 变化的类型，为[entity.ChangeReset]时Row为nil。
+		Op entity.ChangeOp `json:"op"`
+		// Row 新增和更新之后的行，或者删除之前的行，列名为键的JSON对象。
+		Row json.RawMessage `json:"row,omitempty"`
+		// Partial 行超过了NOTIFY的长度限制，Row中只有主键。
+		Partial bool `json:"partial,omitempty"`
+	}
+)
+
+// NewWatchSpec 创建一个监听实体表变化的信息。
+//
+// Params:
+//
+//   - entity: 实体表的名称。
+//   - columns: 转换为实体时查询的列。
+//   - primaries: 主键的列名。
+func NewWatchSpec(entity string, columns []FieldName, primaries ...FieldName) *WatchSpec {
+	return &WatchSpec{
+		Entity: EntitySpec{
+			Name:    entity,
+			Columns: NewFieldSpecs(columns...),
+		},
+		Primaries: primaries,
+	}
+}
+
+// NewWatch 监听实体表的变化。先LISTEN实体表的频道，再创建或者替换实体表上通过NOTIFY发送变化的触发器，
+// 触发器由监听的程序创建，执行生成的SQL时会被删除，之后需要重新调用NewWatch。
+// 连接断开后会自动重新连接，重新连接后会收到[entity.ChangeReset]，ctx结束时关闭连接和返回的通道。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - spec: 监听实体表变化的信息。
+//
+// Returns:
+//
+//	0: 收到的变化。
+//	1: 错误信息。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+//   - Err_0100030013
+func NewWatch(ctx context.Context, drv dialect.Driver, spec *WatchSpec) (<-chan *WatchEvent, error) {
+	if _, err := newTenantScope(ctx, &spec.Entity, ""); err != nil {
+		return nil, err
+	}
+	l, err := NewListener(ctx, drv)
+	if err != nil {
+		return nil, err
+	}
+	channel := spec.channel(ctx)
+	if err := l.Listen(ctx, channel); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := spec.createTrigger(ctx, drv); err != nil {
+		l.Close()
+		return nil, err
+	}
+	events := make(chan *WatchEvent)
+	go func() {
+		defer close(events)
+		defer l.Close()
+		for {
+			var ev *WatchEvent
+			select {
+			case <-ctx.Done():
+				return
+			case n, ok := <-l.Notify():
+				if !ok {
+					return
+				}
+				if n == nil {
+					ev = &WatchEvent{Op: entity.ChangeReset}
+				} else if n.Channel != channel || json.Unmarshal([]byte(n.Payload), &ev) != nil {
+					// 不是触发器发送的通知。
+					continue
+				}
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// Decode 把收到的行转换为实体，行通过jsonb_populate_record转换，列的类型和查询时一致，
+// 行中只有主键时从表中查询最新的行，scan的用法和[RawSpec]中的Scan相同。
+// 设置了租户字段时，行不属于context中的租户，或者行已经不存在时不会调用scan。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+//   - ev: 收到的变化。
+//   - scan: 扫描转换后的行。
+func (spec *WatchSpec) Decode(ctx context.Context, drv dialect.Driver, ev *WatchEvent, scan Scanner) error {
+	raw, err := spec.decodeQuery(ctx, drv.Dialect(), ev)
+	if err != nil {
+		return err
+	}
+	raw.Scan = scan
+	return NewRaw(ctx, drv, raw)
+}
+
+// decodeQuery 生成把收到的行转换为实体的语句。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - d: 数据库方言。
+//   - ev: 收到的变化。
+func (spec *WatchSpec) decodeQuery(ctx context.Context, d dialect.DbDriver, ev *WatchEvent) (*RawSpec, error) {
+	tenant, err := newTenantScope(ctx, &spec.Entity, "")
+	if err != nil {
+		return nil, err
+	}
+	schema := schemaName(ctx, spec.Entity.Schema)
+	b := &Builder{dialect: d}
+	record := func(b *Builder) {
+		b.WriteString("jsonb_populate_record(NULL::")
+		b.WriteSchema(schema).Ident(spec.Entity.Name)
+		b.Comma().Arg(string(ev.Row)).WriteString("::jsonb)")
+	}
+	b.WriteString("SELECT ")
+	for i, c := range spec.Entity.Columns {
+		if i > 0 {
+			b.Comma()
+		}
+		b.WriteString(c.NameFormat(d, b.Quote(c.Name.String())))
+	}
+	b.WriteString(" FROM ")
+	where := " WHERE "
+	if ev.Partial && ev.Op != entity.ChangeDelete {
+		primaries := make([]string, len(spec.Primaries))
+		for i, p := range spec.Primaries {
+			primaries[i] = p.String()
+		}
+		b.WriteSchema(schema).Ident(spec.Entity.Name)
+		b.WriteString(" WHERE ").Wrap(func(b *Builder) {
+			b.IdentComma(primaries...)
+		})
+		b.WriteString(" = (SELECT ").IdentComma(primaries...).WriteString(" FROM ")
+		record(b)
+		b.WriteString(")")
+		where = " AND "
+	} else {
+		record(b)
+		b.WriteString(" AS ").Ident(spec.Entity.Name)
+	}
+	if tenant != nil {
+		b.WriteString(where)
+		tenant.write(b)
+	}
+	return NewRawSpec(b.String(), b.args...), nil
+}
+
+// channel 返回实体表发送通知的频道，为实体表带上模式的名称。
+//
+// Params:
+//
+//   - ctx: 上下文。
+func (spec *WatchSpec) channel(ctx context.Context) string {
+	return qualifiedName(schemaName(ctx, spec.Entity.Schema), spec.Entity.Name)
+}
+
+// createTrigger 在事务中创建或者替换实体表上发送通知的触发器，
+// 同时有多个程序监听时通过事务级的咨询锁避免同时替换触发器。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - drv: 数据库连接。
+func (spec *WatchSpec) createTrigger(ctx context.Context, drv dialect.Driver) error {
+	channel := spec.channel(ctx)
+	tx, err := drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range spec.triggerQueries(ctx, drv.Dialect()) {
+		logSql(&s)
+		if err := tx.Exec(ctx, s.Query, s.Args, nil); err != nil {
+			return Rollback(tx, fmt.Errorf("create watch trigger on %s: %w", channel, err))
+		}
+	}
+	return tx.Commit()
+}
+
+// triggerQueries 生成创建触发器的语句，触发器的名称为"<表名>_watch"，
+// 触发器函数的名称和[entity.TriggerConfig]一样加上"_trigger_func"。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - d: 数据库方言。
+func (spec *WatchSpec) triggerQueries(ctx context.Context, d dialect.DbDriver) []SqlSpec {
+	schema := schemaName(ctx, spec.Entity.Schema)
+	channel := spec.channel(ctx)
+	trigger := spec.Entity.Name + "_watch"
+	qualify := func(b *Builder, name string) *Builder {
+		return b.WriteSchema(schema).Ident(name)
+	}
+	queries := []SqlSpec{}
+
+	b := &Builder{dialect: d}
+	b.WriteString("SELECT pg_advisory_xact_lock(hashtext(").Arg(channel).WriteString("))")
+	queries = append(queries, SqlSpec{Query: b.String(), Args: b.args})
+
+	// 函数体不能使用参数，频道和列名直接写入字符串常量。
+	keys := make([]string, len(spec.Primaries))
+	for i, p := range spec.Primaries {
+		keys[i] = fmt.Sprintf("%s, data->%s", entity.QuoteLiteral(p.String()), entity.QuoteLiteral(p.String()))
+	}
+	b = &Builder{dialect: d}
+	b.WriteString("CREATE OR REPLACE FUNCTION ")
+	qualify(b, trigger+"_trigger_func").WriteString(`() RETURNS TRIGGER AS $func$
+DECLARE
+    data jsonb;
+    payload text;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        data := to_jsonb(OLD);
+    ELSE
+        data := to_jsonb(NEW);
+    END IF;
+    payload := jsonb_build_object('op', TG_OP, 'row', data)::text;
+    IF octet_length(payload) > `)
+	b.WriteString(fmt.Sprint(watchPayloadLimit)).WriteString(` THEN
+        payload := jsonb_build_object('op', TG_OP, 'row', jsonb_build_object(`)
+	b.WriteString(strings.Join(keys, ", ")).WriteString(`), 'partial', true)::text;
+    END IF;
+    PERFORM pg_notify(`)
+	b.WriteString(entity.QuoteLiteral(channel)).WriteString(`, payload);
+    RETURN NULL;
+END;
+$func$ LANGUAGE plpgsql`)
+	queries = append(queries, SqlSpec{Query: b.String()})
+
+	b = &Builder{dialect: d}
+	b.WriteString("DROP TRIGGER IF EXISTS ").Ident(trigger).WriteString(" ON ")
+	qualify(b, spec.Entity.Name)
+	queries = append(queries, SqlSpec{Query: b.String()})
+
+	b = &Builder{dialect: d}
+	b.WriteString("CREATE TRIGGER ").Ident(trigger).WriteString(" AFTER INSERT OR UPDATE OR DELETE ON ")
+	qualify(b, spec.Entity.Name).WriteString(" FOR EACH ROW EXECUTE FUNCTION ")
+	qualify(b, trigger+"_trigger_func").WriteString("()")
+	queries = append(queries, SqlSpec{Query: b.String()})
+	return queries
+}
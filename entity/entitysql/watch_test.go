@@ -0,0 +1,126 @@
+package entitysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/dialect"
+	"github.com/zodileap/taurus_go/entity/dialect/fake"
+)
+
+func newTestWatchSpec() *WatchSpec {
+	spec := NewWatchSpec("users", []FieldName{"id", "name", "tenant_id"}, "id")
+	spec.Entity.Schema = "app"
+	return spec
+}
+
+func TestWatchDecodeQuery(t *testing.T) {
+	spec := newTestWatchSpec()
+	ev := &WatchEvent{Op: entity.ChangeUpdate, Row: []byte(`{"id": 1}`)}
+	raw, err := spec.decodeQuery(context.Background(), dialect.PostgreSQL, ev)
+	if err != nil {
+		t.Fatalf("decodeQuery 返回了意外错误: %v", err)
+	}
+	want := `SELECT "id", "name", "tenant_id" FROM jsonb_populate_record(NULL::"app"."users", $1::jsonb) AS "users"`
+	if raw.Query != want || len(raw.Args) != 1 || raw.Args[0] != `{"id": 1}` {
+		t.Fatalf("转换行的语句不正确: %s %v", raw.Query, raw.Args)
+	}
+
+	spec.Entity.TenantField = "tenant_id"
+	ev.Partial = true
+	raw, err = spec.decodeQuery(entity.WithTenant(context.Background(), int64(7)), dialect.PostgreSQL, ev)
+	if err != nil {
+		t.Fatalf("decodeQuery 返回了意外错误: %v", err)
+	}
+	want = `SELECT "id", "name", "tenant_id" FROM "app"."users" WHERE ("id") = ` +
+		`(SELECT "id" FROM jsonb_populate_record(NULL::"app"."users", $1::jsonb)) AND "tenant_id" = $2`
+	if raw.Query != want || len(raw.Args) != 2 || raw.Args[1] != int64(7) {
+		t.Fatalf("只有主键时应从表中查询: %s %v", raw.Query, raw.Args)
+	}
+	_, err = spec.decodeQuery(context.Background(), dialect.PostgreSQL, ev)
+	requireErrCode(t, err, entity.Err_0100030011)
+}
+
+func TestWatchTriggerQueries(t *testing.T) {
+	queries := newTestWatchSpec().triggerQueries(NewSchemaContext(context.Background(), "tenant_a"), dialect.PostgreSQL)
+	if len(queries) != 4 || queries[0].Args[0] != "tenant_a.users" {
+		t.Fatalf("创建触发器的语句不正确: %v", queries)
+	}
+	function := queries[1].Query
+	for _, s := range []string{
+		`CREATE OR REPLACE FUNCTION "tenant_a"."users_watch_trigger_func"()`,
+		`jsonb_build_object('id', data->'id')`,
+		`pg_notify('tenant_a.users', payload)`,
+	} {
+		if !strings.Contains(function, s) {
+			t.Fatalf("触发器函数中没有 %s: %s", s, function)
+		}
+	}
+	if queries[3].Query != `CREATE TRIGGER "users_watch" AFTER INSERT OR UPDATE OR DELETE ON "tenant_a"."users" `+
+		`FOR EACH ROW EXECUTE FUNCTION "tenant_a"."users_watch_trigger_func"()` {
+		t.Fatalf("创建触发器的语句不正确: %s", queries[3].Query)
+	}
+}
+
+func TestNewWatch(t *testing.T) {
+	drv := fake.New(dialect.PostgreSQL)
+	drv.ExpectExec(`SELECT pg_advisory_xact_lock(hashtext($1))`).WithArgs("app.users")
+	drv.ExpectExec(`CREATE OR REPLACE FUNCTION .*`).Regexp()
+	drv.ExpectExec(`DROP TRIGGER IF EXISTS .*`).Regexp()
+	drv.ExpectExec(`CREATE TRIGGER .*`).Regexp()
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := NewWatch(ctx, drv, newTestWatchSpec())
+	if err != nil {
+		t.Fatalf("NewWatch 返回了意外错误: %v", err)
+	}
+	if err := drv.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if txs := drv.Txs(); len(txs) != 1 || txs[0].State() != fake.TxCommitted {
+		t.Fatal("触发器应在提交的事务中创建")
+	}
+
+	drv.Notify("app.users", `not json`)
+	drv.Notify("app.users", `{"op": "DELETE", "row": {"id": 1}}`)
+	drv.Reconnect()
+	next := func() *WatchEvent {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("没有收到变化")
+		}
+		return nil
+	}
+	if ev := next(); ev.Op != entity.ChangeDelete || string(ev.Row) != `{"id": 1}` || ev.Partial {
+		t.Fatalf("收到的变化不正确: %+v", ev)
+	}
+	if ev := next(); ev.Op != entity.ChangeReset || ev.Row != nil {
+		t.Fatalf("重新连接后应收到ChangeReset: %+v", ev)
+	}
+	cancel()
+	for range events {
+	}
+	if drv.Listening("app.users") != 0 {
+		t.Fatal("ctx结束后应关闭监听的连接")
+	}
+}
+
+func TestNewListenerUnsupported(t *testing.T) {
+	drv := &dsnDriver{Driver: fake.New(dialect.MySQL)}
+	_, err := NewListener(context.Background(), drv)
+	requireErrCode(t, err, entity.Err_0100030013)
+}
+
+// dsnDriver 没有实现dialect.Notifier的驱动。
+type dsnDriver struct {
+	dialect.Driver
+}
+
+func (d *dsnDriver) DSN() string {
+	return "root@tcp(localhost:3306)/test"
+}
@@ -327,6 +327,28 @@ var Err_0100020023 err.ErrCode = err.New(
 	"",
 )
 
+// Err_0100020024 在检查生成的代码时，生成的代码不是最新的。
+//
+// Verbs:
+//
+//	0: 需要更新的文件。
+var Err_0100020024 err.ErrCode = err.New(
+	"0100020024",
+	"generated code is out of date: %s",
+	"1. Run entity generate and commit the generated code.",
+)
+
+// Err_0100020025 加载Schema时使用了不支持的加载方式。
+//
+// Verbs:
+//
+//	0: 加载方式。
+var Err_0100020025 err.ErrCode = err.New(
+	"0100020025",
+	"unsupported schema loader %q",
+	"1. Use one of the loaders: run, binary.",
+)
+
 /**************** CRUD遇到的问题 ***************/
 
 // Err_0100030001 在创建语句中，必填但没有默认值的字段的值为空。
@@ -392,4 +414,85 @@ var Err_0100030007 err.ErrCode = err.New(
 	"",
 )
 
+// Err_0100030008 分批查询时，每批的数量不合法。
+//
+// Verbs:
+//
+//	0: 每批的数量。
+var Err_0100030008 err.ErrCode = err.New(
+	"0100030008",
+	"batch size must be greater than 0, got %d.",
+	"",
+)
+
+// Err_0100030009 根据主键查询实体时，数据库中没有对应的记录。
+//
+// Verbs:
+//
+//	0: 实体表的名字。
+var Err_0100030009 err.ErrCode = err.New(
+	"0100030009",
+	"entity table %s record not found.",
+	"",
+)
+
+// Err_0100030010 Snowflake的节点ID超出范围。
+//
+// Verbs:
+//
+//	0: 节点ID。
+//	1: 节点ID的最大值。
+var Err_0100030010 err.ErrCode = err.New(
+	"0100030010",
+	"snowflake node %d out of range [0, %d].",
+	"",
+)
+
+// Err_0100030011 实体设置了租户字段，但是context中没有租户。
+//
+// Verbs:
+//
+//	0: 实体表的名字。
+var Err_0100030011 err.ErrCode = err.New(
+	"0100030011",
+	"entity table %s requires a tenant in the context.",
+	"",
+)
+
+// Err_0100030012 context中的租户类型和实体的租户字段类型不一致。
+//
+// Verbs:
+//
+//	0: 实体表的名字。
+//	1: 租户字段的类型。
+//	2: context中的租户。
+var Err_0100030012 err.ErrCode = err.New(
+	"0100030012",
+	"entity table %s tenant must be %s, got %T.",
+	"",
+)
+
+// Err_0100030013 驱动不支持LISTEN/NOTIFY，无法监听实体表的变化。
+//
+// Verbs:
+//
+//	0: 数据库类型。
+var Err_0100030013 err.ErrCode = err.New(
+	"0100030013",
+	"driver %s does not support LISTEN/NOTIFY.",
+	"",
+)
+
+// Err_0100030014 实体不满足CHECK约束，在保存之前检查。
+//
+// Verbs:
+//
+//	0: 实体表的名字。
+//	1: 约束的名字。
+var Err_0100030014 err.ErrCode = err.New(
+	"0100030014",
+	"entity table %s violates check constraint %s.",
+	"",
+)
+
 /**************** dialect遇到的问题 ***************/
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/zodileap/taurus_go/entity"
+	"github.com/zodileap/taurus_go/entity/check"
 	"github.com/zodileap/taurus_go/entity/dialect"
 )
 
@@ -137,6 +138,37 @@ func (b *BaseBuilder[T]) IndexMethod(method string) *BaseBuilder[T] {
 	return b
 }
 
+// DefaultExpr 使用SQL表达式作为字段的默认值，例如"now()"、"gen_random_uuid()"。
+// 没有设置字段的值时，新增语句中不会包含这个字段，由数据库计算后通过RETURNING读取。
+//
+// Params:
+//
+//   - expr: 默认值的SQL表达式。
+func (b *BaseBuilder[T]) DefaultExpr(expr string) *BaseBuilder[T] {
+	if b == nil {
+		panic("taurus_go/entity field default: nil pointer dereference.")
+	}
+	b.desc.Default = true
+	b.desc.DefaultValue = expr
+	return b
+}
+
+// Generated 设置字段为生成列GENERATED ALWAYS AS (expr) STORED，字段是只读的，
+// 值由数据库根据同一行的其他字段计算，新增和更新时不会写入，新增后通过RETURNING读取。
+//
+// Params:
+//
+//   - expr: 生成列的SQL表达式，例如"price * quantity"。
+func (b *BaseBuilder[T]) Generated(expr string) *BaseBuilder[T] {
+	if b == nil {
+		panic("taurus_go/entity field generated: nil pointer dereference.")
+	}
+	b.desc.Default = true
+	b.desc.Locked = true
+	b.desc.Generated = expr
+	return b
+}
+
 // CheckBuilder 是检查约束的构建器函数类型
 type CheckBuilder func(fieldName string) string
 
@@ -159,6 +191,29 @@ func (b *BaseBuilder[T]) Check(builder CheckBuilder) *BaseBuilder[T] {
 	return b
 }
 
+// CheckExpr 使用check包的表达式添加CHECK约束到字段，和Check不同，
+// 生成的代码会在保存之前检查约束，不满足时返回错误，不用等到数据库拒绝。
+//
+// Params:
+//
+//   - build: 接收字段的表达式，返回约束的条件，例如：
+//     func(v check.Expr) check.Cond { return v.GTE(0) }
+func (b *BaseBuilder[T]) CheckExpr(build func(v check.Expr) check.Cond) *BaseBuilder[T] {
+	if b == nil {
+		panic("taurus_go/entity field check: nil pointer dereference.")
+	}
+	fieldName := b.desc.AttrName
+	if fieldName == "" {
+		fieldName = b.desc.Name
+	}
+	expr, err := build(check.Col(fieldName)).MarshalJSON()
+	if err != nil {
+		panic(fmt.Sprintf("taurus_go/entity field check: %v", err))
+	}
+	b.desc.CheckExpr = expr
+	return b
+}
+
 type BaseStorage[T any] struct {
 	value *T
 }
@@ -44,3 +44,48 @@ func TestBaseBuilderAndStorage(t *testing.T) {
 		t.Fatalf("MarshalJSON 结果不正确: %s", string(data))
 	}
 }
+
+func TestBaseBuilderDatabaseValues(t *testing.T) {
+	total := &Int64{}
+	desc := &entity.Descriptor{Name: "Total"}
+	total.Init(desc)
+	total.Generated("price * qty")
+	if !desc.Default || !desc.Locked || desc.Generated != "price * qty" {
+		t.Fatalf("生成列的描述信息不正确: %+v", desc)
+	}
+
+	id := &Int64{}
+	desc = &entity.Descriptor{Name: "ID"}
+	id.Init(desc)
+	id.Identity()
+	if !desc.Default || !desc.Identity || desc.DefaultValue != "" {
+		t.Fatalf("标识列的描述信息不正确: %+v", desc)
+	}
+
+	created := &Timestamptz{}
+	desc = &entity.Descriptor{Name: "Created"}
+	created.Init(desc)
+	created.DefaultExpr("now()")
+	if !desc.Default || desc.DefaultValue != "now()" {
+		t.Fatalf("默认值表达式的描述信息不正确: %+v", desc)
+	}
+}
+
+func TestVarcharEnumAndMinLen(t *testing.T) {
+	builder := &VarcharBuilder[string]{}
+	desc := &entity.Descriptor{Name: "status"}
+	if err := builder.Init(desc); err != nil {
+		t.Fatalf("Init 失败: %v", err)
+	}
+	builder.MinLen(2).Enum("draft", "post")
+	if desc.MinLen != 2 || len(desc.Enums) != 2 {
+		t.Fatalf("Builder 描述信息不正确: %+v", desc)
+	}
+	validate := desc.Validators[1].(func(string) error)
+	if err := validate("post"); err != nil {
+		t.Fatalf("可选值验证失败: %v", err)
+	}
+	if err := validate("deleted"); err == nil {
+		t.Fatal("不在可选值中的值应返回错误")
+	}
+}
@@ -188,6 +188,7 @@ func (i *IntBuilder[T]) Default(value T) *IntBuilder[T] {
 
 // Sequence 设置字段的序列。
 // 如果序列不存在，则会自动创建序列。
+// 使用[entity.NewSnowflakeSequence]时，值在Go中生成，数据库中没有默认值。
 // 优先级高于[Default]。
 //
 // Params:
@@ -195,8 +196,20 @@ func (i *IntBuilder[T]) Default(value T) *IntBuilder[T] {
 //   - s: 序列。
 func (i *IntBuilder[T]) Sequence(s entity.Sequence) *IntBuilder[T] {
 	i.desc.Default = true
-	i.desc.DefaultValue = fmt.Sprintf(`%s()`, *s.Name)
 	i.desc.Sequence = s
+	if s.IsClient() {
+		i.desc.DefaultValue = ""
+		return i
+	}
+	i.desc.DefaultValue = fmt.Sprintf(`%s()`, *s.Name)
+	return i
+}
+
+// Identity 设置字段为标识列GENERATED BY DEFAULT AS IDENTITY，不能和[Default]、[Sequence]一起使用。
+// 没有设置字段的值时，新增语句中不会包含这个字段，由数据库生成后通过RETURNING读取。
+func (i *IntBuilder[T]) Identity() *IntBuilder[T] {
+	i.desc.Default = true
+	i.desc.Identity = true
 	return i
 }
 
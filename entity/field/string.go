@@ -3,8 +3,10 @@ package field
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
+	"github.com/zodileap/taurus_go/entity"
 	"github.com/zodileap/taurus_go/entity/dialect"
 )
 
@@ -116,6 +118,7 @@ func (s *VarcharBuilder[T]) MaxLen(i int64) *VarcharBuilder[T] {
 //
 //   - size: 字段的最小长度。
 func (s *VarcharBuilder[T]) MinLen(i int) *VarcharBuilder[T] {
+	s.desc.MinLen = int64(i)
 	s.desc.Validators = append(s.desc.Validators, func(v string) error {
 		if len(v) < i {
 			return errors.New("value is less than the required length")
@@ -125,6 +128,22 @@ func (s *VarcharBuilder[T]) MinLen(i int) *VarcharBuilder[T] {
 	return s
 }
 
+// Enum 设置字段可以选择的值，值不在其中时验证失败。
+//
+// Params:
+//
+//   - values: 字段可以选择的值。
+func (s *VarcharBuilder[T]) Enum(values ...string) *VarcharBuilder[T] {
+	s.desc.Enums = values
+	s.desc.Validators = append(s.desc.Validators, func(v string) error {
+		if !slices.Contains(values, v) {
+			return errors.New("value is not one of the enum values")
+		}
+		return nil
+	})
+	return s
+}
+
 // Required 是否非空,默认可以为null,如果调用[Required],则字段为非空字段。
 func (s *VarcharBuilder[T]) Required() *VarcharBuilder[T] {
 	s.desc.Required = true
@@ -170,6 +189,20 @@ func (s *VarcharBuilder[T]) Locked() *VarcharBuilder[T] {
 	return s
 }
 
+// Sequence 设置字段在Go中生成值，支持[entity.NewUUIDv7Sequence]和[entity.NewULIDSequence]。
+// 插入数据时，如果没有设置字段的值，会在创建实体时生成。
+// 优先级高于[Default]。
+//
+// Params:
+//
+//   - seq: 序列。
+func (s *VarcharBuilder[T]) Sequence(seq entity.Sequence) *VarcharBuilder[T] {
+	s.desc.Default = true
+	s.desc.DefaultValue = ""
+	s.desc.Sequence = seq
+	return s
+}
+
 // Unique 设置字段为唯一字段或参与联合唯一约束。
 // 相同的序号表示这些字段组成联合唯一约束。
 func (s *VarcharBuilder[T]) Unique(index int) *VarcharBuilder[T] {
@@ -328,6 +361,20 @@ func (u *UUIDBuilder[T]) Locked() *UUIDBuilder[T] {
 	return u
 }
 
+// Sequence 设置字段在Go中生成值，支持[entity.NewUUIDv7Sequence]。
+// 插入数据时，如果没有设置字段的值，会在创建实体时生成。
+// 优先级高于[Default]。
+//
+// Params:
+//
+//   - s: 序列。
+func (u *UUIDBuilder[T]) Sequence(s entity.Sequence) *UUIDBuilder[T] {
+	u.desc.Default = true
+	u.desc.DefaultValue = ""
+	u.desc.Sequence = s
+	return u
+}
+
 // Unique 设置字段为唯一字段或参与联合唯一约束。
 // 相同的序号表示这些字段组成联合唯一约束。
 func (u *UUIDBuilder[T]) Unique(index int) *UUIDBuilder[T] {
@@ -375,6 +422,7 @@ func (s *TextBuilder[T]) Name(name string) *TextBuilder[T] {
 //
 //   - size: 字段的最小长度。
 func (s *TextBuilder[T]) MinLen(i int) *TextBuilder[T] {
+	s.desc.MinLen = int64(i)
 	s.desc.Validators = append(s.desc.Validators, func(v string) error {
 		if len(v) < i {
 			return errors.New("value is less than the required length")
@@ -384,6 +432,22 @@ func (s *TextBuilder[T]) MinLen(i int) *TextBuilder[T] {
 	return s
 }
 
+// Enum 设置字段可以选择的值，值不在其中时验证失败。
+//
+// Params:
+//
+//   - values: 字段可以选择的值。
+func (s *TextBuilder[T]) Enum(values ...string) *TextBuilder[T] {
+	s.desc.Enums = values
+	s.desc.Validators = append(s.desc.Validators, func(v string) error {
+		if !slices.Contains(values, v) {
+			return errors.New("value is not one of the enum values")
+		}
+		return nil
+	})
+	return s
+}
+
 // Required 是否非空,默认可以为null,如果调用[Required],则字段为非空字段。
 func (s *TextBuilder[T]) Required() *TextBuilder[T] {
 	s.desc.Required = true
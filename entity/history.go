@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	// HistoryActorSetting 记录操作人的PostgreSQL配置参数，
+	// 事务开始时通过set_config设置为[WithActor]中的操作人，历史表的触发器从这个参数中读取操作人。
+	HistoryActorSetting = "taurus.actor"
+	// HistoryTableSuffix 历史表名称的后缀，历史表的名称为实体表的名称加上这个后缀。
+	HistoryTableSuffix = "_history"
+)
+
+// HistoryOperation 历史记录的操作类型。
+type HistoryOperation string
+
+const (
+	// HistoryInsert 新增。
+	HistoryInsert HistoryOperation = "INSERT"
+	// HistoryUpdate 更新。
+	HistoryUpdate HistoryOperation = "UPDATE"
+	// HistoryDelete 删除。
+	HistoryDelete HistoryOperation = "DELETE"
+)
+
+// HistoryRecord 历史表中的一条记录，每次新增、更新和删除实体都会记录一条。
+type HistoryRecord struct {
+	// ID 历史记录的ID，按照操作的顺序递增。
+	ID int64
+	// Operation 操作类型。
+	Operation HistoryOperation
+	// EntityID 实体的主键，转换为字符串。
+	EntityID string
+	// Old 操作之前的行，列名为键的JSON对象，新增时为nil。
+	Old json.RawMessage
+	// New 操作之后的行，列名为键的JSON对象，删除时为nil。
+	New json.RawMessage
+	// Actor 操作人，context中没有操作人时为空字符串。
+	Actor string
+	// ChangedAt 操作的时间。
+	ChangedAt time.Time
+}
+
+// actorContextKey 用于在context中存储操作人。
+type actorContextKey struct{}
+
+// WithActor 将操作人添加到context中，并返回一个新的context。
+// 开启了[EntityConfig.History]的实体，在这个context中保存时会把操作人记录到历史表中。
+//
+// Params:
+//
+//   - parent: 父context。
+//   - actor: 操作人，例如用户的ID。
+//
+// Returns:
+//
+//	0: 新的context。
+func WithActor(parent context.Context, actor string) context.Context {
+	return context.WithValue(parent, actorContextKey{}, actor)
+}
+
+// ActorFromContext 从context中获取操作人。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//
+// Returns:
+//
+//	0: 操作人。
+//	1: context中是否有操作人。
+func ActorFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	actor, ok := ctx.Value(actorContextKey{}).(string)
+	return actor, ok
+}
+
+// Changed 返回更新时值发生了变化的列，新增和删除时返回所有的列。
+//
+// Returns:
+//
+//	0: 列名，按照名称排序。
+//	1: 行不是JSON对象时返回错误。
+func (r *HistoryRecord) Changed() ([]string, error) {
+	var old, new map[string]json.RawMessage
+	if r.Old != nil {
+		if err := json.Unmarshal(r.Old, &old); err != nil {
+			return nil, err
+		}
+	}
+	if r.New != nil {
+		if err := json.Unmarshal(r.New, &new); err != nil {
+			return nil, err
+		}
+	}
+	columns := []string{}
+	for c, v := range new {
+		if o, ok := old[c]; !ok || string(o) != string(v) {
+			columns = append(columns, c)
+		}
+	}
+	for c := range old {
+		if _, ok := new[c]; !ok {
+			columns = append(columns, c)
+		}
+	}
+	slices.Sort(columns)
+	return columns, nil
+}
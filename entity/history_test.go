@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestActor(t *testing.T) {
+	if _, ok := ActorFromContext(context.Background()); ok {
+		t.Fatal("context中没有操作人时应返回false")
+	}
+	actor, ok := ActorFromContext(WithActor(context.Background(), "alice"))
+	if !ok || actor != "alice" {
+		t.Fatalf("获取操作人失败: %v %v", actor, ok)
+	}
+}
+
+func TestHistoryRecordChanged(t *testing.T) {
+	r := &HistoryRecord{
+		Operation: HistoryUpdate,
+		Old:       []byte(`{"id": 1, "name": "a", "age": 3}`),
+		New:       []byte(`{"id": 1, "name": "b", "age": 3}`),
+	}
+	columns, err := r.Changed()
+	if err != nil || !reflect.DeepEqual(columns, []string{"name"}) {
+		t.Fatalf("更新时变化的列不正确: %v %v", columns, err)
+	}
+	r = &HistoryRecord{Operation: HistoryDelete, Old: []byte(`{"id": 1, "name": "a"}`)}
+	columns, err = r.Changed()
+	if err != nil || !reflect.DeepEqual(columns, []string{"id", "name"}) {
+		t.Fatalf("删除时应返回所有的列: %v %v", columns, err)
+	}
+}
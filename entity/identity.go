@@ -0,0 +1,84 @@
+package entity
+
+import "sync"
+
+type (
+	// IdentityMap 实体的一级缓存。在同一个数据库实例中，
+	// 同一实体类型、同一主键的记录只对应一个实体实例，多次查询返回同一个指针。
+	IdentityMap struct {
+		mu       sync.RWMutex
+		entities map[identityKey]any
+	}
+
+	// identityKey 实体在一级缓存中的键。
+	identityKey struct {
+		entity string
+		key    string
+	}
+)
+
+// NewIdentityMap 创建一个一级缓存。
+func NewIdentityMap() *IdentityMap {
+	return &IdentityMap{entities: make(map[identityKey]any)}
+}
+
+// Get 获取缓存的实体。
+//
+// Params:
+//
+//   - entity: 实体表的名字。
+//   - key: 主键的字符串表示。
+//
+// Returns:
+//
+//	0: 缓存的实体。
+//	1: 是否存在。
+func (m *IdentityMap) Get(entity string, key string) (any, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entities[identityKey{entity, key}]
+	return e, ok
+}
+
+// Set 缓存实体，已经存在的实体会被覆盖。
+//
+// Params:
+//
+//   - entity: 实体表的名字。
+//   - key: 主键的字符串表示。
+//   - e: 实体。
+func (m *IdentityMap) Set(entity string, key string, e any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities[identityKey{entity, key}] = e
+}
+
+// Delete 删除缓存的实体，只有缓存的实体就是e时才会删除。
+//
+// Params:
+//
+//   - entity: 实体表的名字。
+//   - key: 主键的字符串表示。
+//   - e: 实体。
+func (m *IdentityMap) Delete(entity string, key string, e any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := identityKey{entity, key}
+	if v, ok := m.entities[k]; ok && v == e {
+		delete(m.entities, k)
+	}
+}
+
+// Len 获取缓存的实体数量。
+func (m *IdentityMap) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entities)
+}
+
+// Clear 清空缓存，一般在一个工作单元结束后调用。
+func (m *IdentityMap) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities = make(map[identityKey]any)
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "testing"
+
+func TestIdentityMap(t *testing.T) {
+	m := NewIdentityMap()
+	a, b := &struct{ n int }{1}, &struct{ n int }{2}
+
+	m.Set("user", "1", a)
+	if v, ok := m.Get("user", "1"); !ok || v != a {
+		t.Fatalf("缓存的实体不正确: %v %v", v, ok)
+	}
+	if _, ok := m.Get("blog", "1"); ok {
+		t.Fatal("不同实体类型的主键不应相互影响")
+	}
+
+	m.Delete("user", "1", b)
+	if _, ok := m.Get("user", "1"); !ok {
+		t.Fatal("删除其他实例时不应删除缓存")
+	}
+	m.Delete("user", "1", a)
+	if m.Len() != 0 {
+		t.Fatalf("删除后缓存数量不正确: %d", m.Len())
+	}
+
+	m.Set("user", "1", a)
+	m.Clear()
+	if m.Len() != 0 {
+		t.Fatal("Clear 后缓存应为空")
+	}
+}
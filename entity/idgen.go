@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SequenceTSID 由数据库中的PL/pgSQL函数生成的TSID，字段类型为Int64。
+	SequenceTSID = "TSID"
+	// SequenceSnowflake 在Go中生成的Snowflake ID，字段类型为Int64。
+	// 节点ID通过Config.SnowflakeNode设置。
+	SequenceSnowflake = "Snowflake"
+	// SequenceUUIDv7 在Go中生成的UUIDv7，字段类型为UUID或者字符串。
+	SequenceUUIDv7 = "UUIDv7"
+	// SequenceULID 在Go中生成的ULID，字段类型为字符串，不能是UUID。
+	SequenceULID = "ULID"
+)
+
+const (
+	// snowflakeEpoch Snowflake ID时间戳的起始时间，2020-01-01 00:00:00 UTC的毫秒数。
+	snowflakeEpoch int64 = 1577836800000
+	// snowflakeNodeBits 节点ID占用的位数。
+	snowflakeNodeBits = 10
+	// snowflakeStepBits 同一毫秒内序号占用的位数。
+	snowflakeStepBits = 12
+	// MaxSnowflakeNode 节点ID的最大值。
+	MaxSnowflakeNode int64 = 1<<snowflakeNodeBits - 1
+)
+
+// ulidEncoding ULID使用的Crockford Base32字符表。
+const ulidEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var snowflake struct {
+	mu   sync.Mutex
+	time int64
+	step int64
+}
+
+// NewSnowflakeSequence 创建一个在Go中生成Snowflake ID的Sequence。
+func NewSnowflakeSequence() Sequence {
+	return Sequence{Mode: SequenceSnowflake}
+}
+
+// NewUUIDv7Sequence 创建一个在Go中生成UUIDv7的Sequence。
+func NewUUIDv7Sequence() Sequence {
+	return Sequence{Mode: SequenceUUIDv7}
+}
+
+// NewULIDSequence 创建一个在Go中生成ULID的Sequence，ULID不是UUID，不能用于UUID字段。
+func NewULIDSequence() Sequence {
+	return Sequence{Mode: SequenceULID}
+}
+
+// IsClient 判断序列的值是否在Go中生成。
+// 在Go中生成的值会在create()时赋给字段，不需要等到插入数据库后才能得到。
+func (s Sequence) IsClient() bool {
+	switch s.Mode {
+	case SequenceSnowflake, SequenceUUIDv7, SequenceULID:
+		return true
+	}
+	return false
+}
+
+// NextSnowflake 生成一个Snowflake ID，由41位毫秒时间戳、10位节点ID和12位序号组成。
+//
+// Returns:
+//
+//	0: Snowflake ID。
+//	1: 节点ID不合法时返回错误。
+func NextSnowflake() (int64, error) {
+	node := int64(0)
+	if n := GetConfig().SnowflakeNode; n != nil {
+		node = *n
+	}
+	if node < 0 || node > MaxSnowflakeNode {
+		return 0, Err_0100030010.Sprintf(node, MaxSnowflakeNode)
+	}
+	snowflake.mu.Lock()
+	defer snowflake.mu.Unlock()
+	now := time.Now().UnixMilli()
+	if now < snowflake.time {
+		// 时钟回拨时继续使用上一次的时间戳，保证ID递增。
+		now = snowflake.time
+	}
+	if now == snowflake.time {
+		snowflake.step = (snowflake.step + 1) & (1<<snowflakeStepBits - 1)
+		if snowflake.step == 0 {
+			for now <= snowflake.time {
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		snowflake.step = 0
+	}
+	snowflake.time = now
+	return (now-snowflakeEpoch)<<(snowflakeNodeBits+snowflakeStepBits) | node<<snowflakeStepBits | snowflake.step, nil
+}
+
+// NewUUIDv7 生成一个UUIDv7。
+//
+// Returns:
+//
+//	0: UUIDv7的字符串表示。
+//	1: 错误信息。
+func NewUUIDv7() (string, error) {
+	u, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// NewULID 生成一个ULID，由48位毫秒时间戳和80位随机数组成，编码为26个字符。
+//
+// Returns:
+//
+//	0: ULID。
+//	1: 错误信息。
+func NewULID() (string, error) {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	// 把128位按5位一组编码，第一个字符只使用3位。
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = ulidEncoding[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNextSnowflake(t *testing.T) {
+	original := cloneConfig(GetConfig())
+	t.Cleanup(func() {
+		SetConfig(original)
+	})
+
+	node := int64(5)
+	SetConfig(Config{SnowflakeNode: &node})
+	last := int64(0)
+	for i := 0; i < 10000; i++ {
+		id, err := NextSnowflake()
+		if err != nil {
+			t.Fatalf("NextSnowflake 返回了意外错误: %v", err)
+		}
+		if id <= last {
+			t.Fatalf("Snowflake ID 应递增: %d <= %d", id, last)
+		}
+		if (id>>snowflakeStepBits)&MaxSnowflakeNode != node {
+			t.Fatalf("Snowflake ID 的节点不正确: %d", id)
+		}
+		last = id
+	}
+
+	invalid := MaxSnowflakeNode + 1
+	SetConfig(Config{SnowflakeNode: &invalid})
+	_, err := NextSnowflake()
+	requireEntityErrCode(t, err, Err_0100030010.Code())
+}
+
+func TestNewUUIDv7AndULID(t *testing.T) {
+	s, err := NewUUIDv7()
+	if err != nil {
+		t.Fatalf("NewUUIDv7 返回了意外错误: %v", err)
+	}
+	if u, err := uuid.Parse(s); err != nil || u.Version() != 7 {
+		t.Fatalf("UUIDv7 不正确: %s %v", s, err)
+	}
+
+	a, err := NewULID()
+	if err != nil {
+		t.Fatalf("NewULID 返回了意外错误: %v", err)
+	}
+	if len(a) != 26 || strings.Trim(a, ulidEncoding) != "" || a[0] > '7' {
+		t.Fatalf("ULID 格式不正确: %s", a)
+	}
+	b, _ := NewULID()
+	if a == b {
+		t.Fatal("ULID 不应重复")
+	}
+}
+
+func TestSequenceIsClient(t *testing.T) {
+	if NewSequence("id_seq").IsClient() {
+		t.Fatal("TSID 由数据库生成")
+	}
+	for _, s := range []Sequence{NewSnowflakeSequence(), NewUUIDv7Sequence(), NewULIDSequence()} {
+		if !s.IsClient() {
+			t.Fatalf("%s 应在 Go 中生成", s.Mode)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package entity
+
+// IndexBuilder 实体表的索引构建器，在实体的Indexes()方法中返回。
+type IndexBuilder interface {
+	// Descriptor codegen中使用，用于获取索引的描述。
+	Descriptor() *IndexDescriptor
+}
+
+// IndexDescriptor 索引的描述。
+type IndexDescriptor struct {
+	// Name 索引的名称，不能为空。
+	Name string
+	// Columns 索引的列，按顺序排列。
+	Columns []IndexColumn
+	// Unique 是否是唯一索引。
+	Unique bool
+	// Method 索引的方法，例如btree、hash、gin、gist，为空时使用btree。
+	Method string
+	// Where 部分索引的条件，例如"deleted_at IS NULL"。
+	Where string
+	// Include 覆盖索引中INCLUDE的字段。
+	Include []FieldBuilder
+	// Concurrently 是否使用CREATE INDEX CONCURRENTLY创建索引，不会锁表，但不能在事务中执行。
+	Concurrently bool
+}
+
+// IndexColumn 索引中的一列，Field和Expr只能设置一个。
+type IndexColumn struct {
+	// Field 索引的字段。
+	Field FieldBuilder
+	// Expr 索引的表达式，例如"lower(email)"。
+	Expr string
+	// Desc 是否降序。
+	Desc bool
+	// Nulls NULL值的排序，"FIRST"或者"LAST"，为空时使用数据库的默认值。
+	Nulls string
+}
+
+// Index 索引。
+type Index struct {
+	desc *IndexDescriptor
+}
+
+// InitIndex 初始化一个索引。
+//
+// Params:
+//
+//   - name: 索引的名称。
+func InitIndex(name string) *Index {
+	return &Index{desc: &IndexDescriptor{Name: name}}
+}
+
+// Descriptor codegen中使用，用于获取索引的描述。
+func (i *Index) Descriptor() *IndexDescriptor {
+	return i.desc
+}
+
+// Fields 按顺序添加索引的字段。
+//
+// Params:
+//
+//   - fs: 索引的字段。
+func (i *Index) Fields(fs ...FieldBuilder) *Index {
+	for _, f := range fs {
+		i.desc.Columns = append(i.desc.Columns, IndexColumn{Field: f})
+	}
+	return i
+}
+
+// Expr 添加一个表达式列，例如"lower(email)"。
+//
+// Params:
+//
+//   - expr: 表达式。
+func (i *Index) Expr(expr string) *Index {
+	i.desc.Columns = append(i.desc.Columns, IndexColumn{Expr: expr})
+	return i
+}
+
+// Desc 设置最后添加的列为降序。
+func (i *Index) Desc() *Index {
+	if c := i.last(); c != nil {
+		c.Desc = true
+	}
+	return i
+}
+
+// NullsFirst 设置最后添加的列的NULL值排在前面。
+func (i *Index) NullsFirst() *Index {
+	if c := i.last(); c != nil {
+		c.Nulls = "FIRST"
+	}
+	return i
+}
+
+// NullsLast 设置最后添加的列的NULL值排在后面。
+func (i *Index) NullsLast() *Index {
+	if c := i.last(); c != nil {
+		c.Nulls = "LAST"
+	}
+	return i
+}
+
+// Unique 设置为唯一索引。
+func (i *Index) Unique() *Index {
+	i.desc.Unique = true
+	return i
+}
+
+// Using 设置索引的方法，例如btree、hash、gin、gist。
+//
+// Params:
+//
+//   - method: 索引的方法。
+func (i *Index) Using(method string) *Index {
+	i.desc.Method = method
+	return i
+}
+
+// Where 设置部分索引的条件，只有满足条件的行才会被索引。
+//
+// Params:
+//
+//   - predicate: 条件，例如"deleted_at IS NULL"。
+func (i *Index) Where(predicate string) *Index {
+	i.desc.Where = predicate
+	return i
+}
+
+// Include 设置覆盖索引中INCLUDE的字段。
+//
+// Params:
+//
+//   - fs: INCLUDE的字段。
+func (i *Index) Include(fs ...FieldBuilder) *Index {
+	i.desc.Include = append(i.desc.Include, fs...)
+	return i
+}
+
+// Concurrently 使用CREATE INDEX CONCURRENTLY创建索引。
+func (i *Index) Concurrently() *Index {
+	i.desc.Concurrently = true
+	return i
+}
+
+// last 返回最后添加的列。
+func (i *Index) last() *IndexColumn {
+	if len(i.desc.Columns) == 0 {
+		return nil
+	}
+	return &i.desc.Columns[len(i.desc.Columns)-1]
+}
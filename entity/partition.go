@@ -0,0 +1,167 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 分区的方式。
+const (
+	// PartitionRange 按范围分区，例如按月分区的时间序列数据。
+	PartitionRange = "RANGE"
+	// PartitionList 按值列表分区。
+	PartitionList = "LIST"
+	// PartitionHash 按哈希分区。
+	PartitionHash = "HASH"
+)
+
+// 范围分区的无界值。
+const (
+	// MinValue 范围分区的下界为无界。
+	MinValue PartitionValue = "MINVALUE"
+	// MaxValue 范围分区的上界为无界。
+	MaxValue PartitionValue = "MAXVALUE"
+)
+
+type (
+	// PartitionConfig 表的分区配置。
+	PartitionConfig struct {
+		// Strategy 分区的方式，PartitionRange、PartitionList或者PartitionHash。
+		Strategy string
+		// Fields 分区键字段在数据库中的名称，比如"created"。
+		// PostgreSQL要求主键和唯一约束包含所有分区键字段。
+		Fields []string
+		// Partitions 创建表时一起创建的分区。
+		Partitions []PartitionBound
+	}
+
+	// PartitionBound 一个分区和它的范围。
+	PartitionBound struct {
+		// Name 分区表的名称。
+		Name string
+		// Bound 分区的范围，例如"FROM ('2026-01-01') TO ('2026-02-01')"，为空时是默认分区。
+		Bound string
+	}
+
+	// PartitionValue 分区范围中不需要加引号的值，例如MINVALUE、MAXVALUE。
+	PartitionValue string
+)
+
+// RangePartition 创建一个范围分区，包含from，不包含to。
+//
+// Params:
+//
+//   - name: 分区表的名称。
+//   - from: 分区的下界。
+//   - to: 分区的上界。
+func RangePartition(name string, from, to any) PartitionBound {
+	return PartitionBound{
+		Name:  name,
+		Bound: fmt.Sprintf("FROM (%s) TO (%s)", PartitionLiteral(from), PartitionLiteral(to)),
+	}
+}
+
+// ListPartition 创建一个值列表分区。
+//
+// Params:
+//
+//   - name: 分区表的名称。
+//   - values: 分区包含的值。
+func ListPartition(name string, values ...any) PartitionBound {
+	vs := make([]string, len(values))
+	for i, v := range values {
+		vs[i] = PartitionLiteral(v)
+	}
+	return PartitionBound{
+		Name:  name,
+		Bound: fmt.Sprintf("IN (%s)", strings.Join(vs, ", ")),
+	}
+}
+
+// HashPartition 创建一个哈希分区。
+//
+// Params:
+//
+//   - name: 分区表的名称。
+//   - modulus: 哈希的模数，通常是哈希分区的数量。
+//   - remainder: 哈希的余数，小于modulus。
+func HashPartition(name string, modulus, remainder int) PartitionBound {
+	return PartitionBound{
+		Name:  name,
+		Bound: fmt.Sprintf("WITH (MODULUS %d, REMAINDER %d)", modulus, remainder),
+	}
+}
+
+// DefaultPartition 创建一个默认分区，不属于其他分区的数据会放到这个分区中。
+//
+// Params:
+//
+//   - name: 分区表的名称。
+func DefaultPartition(name string) PartitionBound {
+	return PartitionBound{Name: name}
+}
+
+// MonthPartition 创建t所在月份的范围分区，分区表的名称是"表名_年_月"，例如"blog_2026_01"。
+//
+// Params:
+//
+//   - table: 分区表的父表名称。
+//   - t: 分区包含的时间。
+func MonthPartition(table string, t time.Time) PartitionBound {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return RangePartition(fmt.Sprintf("%s_%04d_%02d", table, from.Year(), from.Month()), from, from.AddDate(0, 1, 0))
+}
+
+// MonthPartitions 从start所在的月份开始，创建连续months个月的范围分区。
+//
+// Params:
+//
+//   - table: 分区表的父表名称。
+//   - start: 第一个分区包含的时间。
+//   - months: 分区的数量。
+func MonthPartitions(table string, start time.Time, months int) []PartitionBound {
+	ps := make([]PartitionBound, 0, months)
+	for i := 0; i < months; i++ {
+		ps = append(ps, MonthPartition(table, start.AddDate(0, i, 0)))
+	}
+	return ps
+}
+
+// SQL 返回创建分区时"FOR VALUES"的部分。
+func (p PartitionBound) SQL() string {
+	if p.Bound == "" {
+		return "DEFAULT"
+	}
+	return "FOR VALUES " + p.Bound
+}
+
+// PartitionLiteral 把分区范围的值转换为SQL中的字面量，字符串和时间会加上单引号。
+//
+// Params:
+//
+//   - v: 分区范围的值。
+func PartitionLiteral(v any) string {
+	switch v := v.(type) {
+	case PartitionValue:
+		return string(v)
+	case time.Time:
+		return QuoteLiteral(v.Format("2006-01-02 15:04:05.999999-07:00"))
+	case string:
+		return QuoteLiteral(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return fmt.Sprint(v)
+	default:
+		return QuoteLiteral(fmt.Sprint(v))
+	}
+}
+
+// QuoteLiteral 把字符串转换为SQL的字符串常量，给字符串加上单引号，字符串中的单引号会被转义。
+// 用于不能使用参数的语句，例如分区的范围和函数体。
+//
+// Params:
+//
+//   - s: 字符串。
+func QuoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
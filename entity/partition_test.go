@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionBoundSQL(t *testing.T) {
+	cases := []struct {
+		bound PartitionBound
+		want  string
+	}{
+		{RangePartition("p0", MinValue, 100), "FOR VALUES FROM (MINVALUE) TO (100)"},
+		{ListPartition("p_cn", "cn", "o'k"), "FOR VALUES IN ('cn', 'o''k')"},
+		{HashPartition("p1", 4, 1), "FOR VALUES WITH (MODULUS 4, REMAINDER 1)"},
+		{DefaultPartition("p_default"), "DEFAULT"},
+	}
+	for _, c := range cases {
+		if got := c.bound.SQL(); got != c.want {
+			t.Fatalf("分区 %s 的范围不正确: %s", c.bound.Name, got)
+		}
+	}
+}
+
+func TestMonthPartitions(t *testing.T) {
+	ps := MonthPartitions("metric", time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC), 3)
+	names := []string{"metric_2026_11", "metric_2026_12", "metric_2027_01"}
+	if len(ps) != len(names) {
+		t.Fatalf("分区数量不正确: %d", len(ps))
+	}
+	for i, name := range names {
+		if ps[i].Name != name {
+			t.Fatalf("第%d个分区的名称不正确: %s", i, ps[i].Name)
+		}
+	}
+	if ps[2].Bound != "FROM ('2027-01-01 00:00:00+00:00') TO ('2027-02-01 00:00:00+00:00')" {
+		t.Fatalf("分区的范围不正确: %s", ps[2].Bound)
+	}
+}
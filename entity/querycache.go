@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// QueryCache 查询结果的缓存，entity/querycache中提供了cache/lru和cache/redis的适配器。
+// 查询通过Cache(ttl)开启缓存，键为生成的SQL和参数，Save修改了实体表之后会清除相关的缓存。
+type QueryCache interface {
+	// Get 获取缓存的查询结果。
+	//
+	// Params:
+	//
+	//   - ctx: 上下文。
+	//   - key: 缓存的键。
+	//
+	// Returns:
+	//
+	//	0: 缓存的查询结果。
+	//	1: 是否存在未过期的缓存。
+	//	2: 错误信息。
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 缓存查询结果，tables为查询中使用的表，用于按表清除缓存。
+	//
+	// Params:
+	//
+	//   - ctx: 上下文。
+	//   - key: 缓存的键。
+	//   - value: 查询结果。
+	//   - ttl: 过期时间。
+	//   - tables: 查询中使用的表，带有模式名称时为"<模式>.<表名>"。
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tables []string) error
+	// Invalidate 清除使用了这些表的缓存。
+	//
+	// Params:
+	//
+	//   - ctx: 上下文。
+	//   - tables: 修改了的表，格式和Set中的tables相同。
+	Invalidate(ctx context.Context, tables ...string) error
+}
+
+// caches 通过SetQueryCache设置的查询缓存。
+var caches map[string]QueryCache = make(map[string]QueryCache)
+
+// SetQueryCache 设置tag的数据库使用的查询缓存，需要在创建数据库之前设置，c为nil时不使用缓存。
+//
+// Params:
+//
+//   - tag: 数据库的标签。
+//   - c: 查询缓存。
+//
+// ErrCodes:
+//
+//   - Err_0100010001
+func SetQueryCache(tag string, c QueryCache) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if tag == "" {
+		return Err_0100010001
+	}
+	if c == nil {
+		delete(caches, tag)
+		return nil
+	}
+	caches[tag] = c
+	return nil
+}
+
+// GetQueryCache 获取tag的数据库使用的查询缓存。
+//
+// Params:
+//
+//   - tag: 数据库的标签。
+//
+// Returns:
+//
+//	0: 查询缓存，没有设置时为nil。
+func GetQueryCache(tag string) QueryCache {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return caches[tag]
+}
@@ -0,0 +1,113 @@
+// Package querycache 提供[entity.QueryCache]的适配器，把查询结果缓存在cache/lru的进程内缓存，
+// 或者cache/redis的Redis中，通过[entity.SetQueryCache]设置给数据库使用。
+package querycache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zodileap/taurus_go/cache/lru"
+	"github.com/zodileap/taurus_go/entity"
+)
+
+// lruEntry 缓存在lru.Cache中的查询结果。
+type lruEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// LRU 使用cache/lru实现的[entity.QueryCache]，只在当前进程中有效，
+// 多个进程使用同一个数据库时需要使用[Redis]，否则其他进程的修改不会清除缓存。
+type LRU struct {
+	cache    *lru.Cache[string, lruEntry]
+	capacity int
+	// mu 保护tables。
+	mu sync.Mutex
+	// tables 表名到缓存的键的索引，用于按表清除缓存。
+	tables map[string]map[string]struct{}
+}
+
+var _ entity.QueryCache = (*LRU)(nil)
+
+// NewLRU 创建一个使用cache/lru的查询缓存。
+//
+// Params:
+//
+//   - capacity: 最多缓存的查询结果的数量，超过时淘汰最久没有使用的结果。
+//
+// Returns:
+//
+//	0: 查询缓存。
+//	1: capacity不是正数时返回lru.ErrInvalidCapacity。
+func NewLRU(capacity int) (*LRU, error) {
+	c, err := lru.NewCache[string, lruEntry](capacity)
+	if err != nil {
+		return nil, err
+	}
+	return &LRU{
+		cache:    c,
+		capacity: capacity,
+		tables:   make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// Get 实现了[entity.QueryCache]，过期的结果会被删除。
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	e, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if !time.Now().Before(e.expires) {
+		c.cache.Delete(key)
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+// Set 实现了[entity.QueryCache]。
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tables []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Put(key, lruEntry{value: value, expires: time.Now().Add(ttl)})
+	for _, t := range tables {
+		keys, ok := c.tables[t]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tables[t] = keys
+		}
+		keys[key] = struct{}{}
+		if len(keys) > 2*c.capacity {
+			c.prune(keys)
+		}
+	}
+	return nil
+}
+
+// Invalidate 实现了[entity.QueryCache]。
+func (c *LRU) Invalidate(ctx context.Context, tables ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, t := range tables {
+		for key := range c.tables[t] {
+			c.cache.Delete(key)
+		}
+		delete(c.tables, t)
+	}
+	return nil
+}
+
+// prune 从索引中删除已经被淘汰的键，避免一直没有修改的表的索引无限增长。
+//
+// Params:
+//
+//   - keys: 表的索引。
+func (c *LRU) prune(keys map[string]struct{}) {
+	for key := range keys {
+		if !c.cache.Contains(key) {
+			delete(keys, key)
+		}
+	}
+}
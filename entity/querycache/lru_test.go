@@ -0,0 +1,39 @@
+package querycache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewLRU(0); err == nil {
+		t.Fatal("容量不是正数时应返回错误")
+	}
+	c, err := NewLRU(2)
+	if err != nil {
+		t.Fatalf("NewLRU 返回了意外错误: %v", err)
+	}
+	c.Set(ctx, "a", []byte("1"), time.Minute, []string{"app.users"})
+	c.Set(ctx, "b", []byte("2"), time.Minute, []string{"app.users", "app.blogs"})
+	c.Set(ctx, "c", []byte("3"), -time.Second, []string{"app.blogs"})
+	if v, ok, _ := c.Get(ctx, "b"); !ok || string(v) != "2" {
+		t.Fatalf("缓存的结果不正确: %s %v", v, ok)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("超过容量时应淘汰最久没有使用的结果")
+	}
+	if _, ok, _ := c.Get(ctx, "c"); ok {
+		t.Fatal("过期的结果不应返回")
+	}
+
+	c.Set(ctx, "a", []byte("1"), time.Minute, []string{"app.users"})
+	c.Invalidate(ctx, "app.blogs")
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Fatal("清除表之后不应返回使用了这个表的结果")
+	}
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("清除表时不应清除其他表的结果")
+	}
+}
@@ -0,0 +1,108 @@
+package querycache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/zodileap/taurus_go/cache/redis"
+	"github.com/zodileap/taurus_go/entity"
+)
+
+// DefaultRedisPrefix Redis中查询缓存的键的默认前缀。
+const DefaultRedisPrefix = "taurus:query:"
+
+// Redis 使用cache/redis实现的[entity.QueryCache]，多个进程共享缓存和清除。
+// 查询结果保存在"<前缀>r:<键>"中，每个表使用的键保存在集合"<前缀>t:<表名>"中。
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+var _ entity.QueryCache = (*Redis)(nil)
+
+// NewRedis 创建一个使用cache/redis的查询缓存。
+//
+// Params:
+//
+//   - client: 通过redis.GetClient获取的客户端。
+//   - prefix: 键的前缀，为空时使用[DefaultRedisPrefix]。
+//
+// Returns:
+//
+//	0: 查询缓存。
+func NewRedis(client *redis.Client, prefix string) *Redis {
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Get 实现了[entity.QueryCache]。
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Client.Get(ctx, c.resultKey(key)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set 实现了[entity.QueryCache]，表的集合的过期时间会延长到不短于结果的过期时间。
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tables []string) error {
+	result := c.resultKey(key)
+	pipe := c.client.Client.TxPipeline()
+	pipe.Set(ctx, result, value, ttl)
+	ttls := make([]*goredis.DurationCmd, len(tables))
+	for i, t := range tables {
+		pipe.SAdd(ctx, c.tableKey(t), result)
+		ttls[i] = pipe.TTL(ctx, c.tableKey(t))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	for i, t := range tables {
+		// 集合没有过期时间时TTL返回-1，同样需要设置。
+		if ttls[i].Val() < ttl {
+			if err := c.client.Client.Expire(ctx, c.tableKey(t), ttl).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Invalidate 实现了[entity.QueryCache]。
+func (c *Redis) Invalidate(ctx context.Context, tables ...string) error {
+	for _, t := range tables {
+		keys, err := c.client.Client.SMembers(ctx, c.tableKey(t)).Result()
+		if err != nil {
+			return err
+		}
+		if err := c.client.Client.Del(ctx, append(keys, c.tableKey(t))...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resultKey 返回保存查询结果的键。
+//
+// Params:
+//
+//   - key: 缓存的键。
+func (c *Redis) resultKey(key string) string {
+	return c.prefix + "r:" + key
+}
+
+// tableKey 返回保存表使用的键的集合。
+//
+// Params:
+//
+//   - table: 表名。
+func (c *Redis) tableKey(table string) string {
+	return c.prefix + "t:" + table
+}
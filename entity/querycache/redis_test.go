@@ -0,0 +1,62 @@
+package querycache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+
+	"github.com/zodileap/taurus_go/cache/redis"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("启动 miniredis 失败: %v", err)
+	}
+	t.Cleanup(server.Close)
+	t.Cleanup(redis.ClearClient)
+	redis.SetClient(t.Name(), &redis.Options{Addr: server.Addr()})
+	client, err := redis.GetClient(t.Name())
+	if err != nil {
+		t.Fatalf("获取测试客户端失败: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewRedis(client, ""), server
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestRedis(t)
+	if _, ok, err := c.Get(ctx, "a"); ok || err != nil {
+		t.Fatalf("没有缓存时应返回false: %v %v", ok, err)
+	}
+	if err := c.Set(ctx, "a", []byte("1"), time.Minute, []string{"app.users"}); err != nil {
+		t.Fatalf("Set 返回了意外错误: %v", err)
+	}
+	if err := c.Set(ctx, "b", []byte("2"), time.Hour, []string{"app.users", "app.blogs"}); err != nil {
+		t.Fatalf("Set 返回了意外错误: %v", err)
+	}
+	if v, ok, err := c.Get(ctx, "a"); !ok || err != nil || string(v) != "1" {
+		t.Fatalf("缓存的结果不正确: %s %v %v", v, ok, err)
+	}
+	if ttl := server.TTL(DefaultRedisPrefix + "t:app.users"); ttl != time.Hour {
+		t.Fatalf("表的集合的过期时间应延长到最长的结果: %v", ttl)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("过期的结果不应返回")
+	}
+	if err := c.Invalidate(ctx, "app.blogs"); err != nil {
+		t.Fatalf("Invalidate 返回了意外错误: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Fatal("清除表之后不应返回使用了这个表的结果")
+	}
+	if server.Exists(DefaultRedisPrefix + "t:app.blogs") {
+		t.Fatal("清除表之后应删除表的集合")
+	}
+}
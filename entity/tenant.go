@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+)
+
+// tenantContextKey 用于在context中存储租户。
+type tenantContextKey struct{}
+
+// WithTenant 将租户添加到context中，并返回一个新的context。
+// 设置了[EntityConfig.TenantField]的实体，会使用这个租户过滤和设置租户字段。
+//
+// Params:
+//
+//   - parent: 父context。
+//   - tenant: 租户，类型需要和租户字段的值类型一致。
+//
+// Returns:
+//
+//	0: 新的context。
+func WithTenant(parent context.Context, tenant any) context.Context {
+	return context.WithValue(parent, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext 从context中获取租户。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//
+// Returns:
+//
+//	0: 租户。
+//	1: context中是否有租户。
+func TenantFromContext(ctx context.Context) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tenant := ctx.Value(tenantContextKey{})
+	return tenant, tenant != nil
+}
+
+// Tenant 从context中获取指定类型的租户，用于新增实体时设置租户字段。
+//
+// Params:
+//
+//   - ctx: 上下文。
+//   - entity: 实体表的名字。
+//
+// Returns:
+//
+//	0: 租户。
+//	1: context中没有租户，或者租户类型不一致时返回错误。
+//
+// ErrCodes:
+//
+//   - Err_0100030011
+//   - Err_0100030012
+func Tenant[T any](ctx context.Context, entity string) (T, error) {
+	var t T
+	v, ok := TenantFromContext(ctx)
+	if !ok {
+		return t, Err_0100030011.Sprintf(entity)
+	}
+	t, ok = v.(T)
+	if !ok {
+		return t, Err_0100030012.Sprintf(entity, fmt.Sprintf("%T", t), v)
+	}
+	return t, nil
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTenant(t *testing.T) {
+	_, err := Tenant[int64](context.Background(), "users")
+	requireEntityErrCode(t, err, Err_0100030011.Code())
+
+	ctx := WithTenant(context.Background(), int64(7))
+	tenant, err := Tenant[int64](ctx, "users")
+	if err != nil || tenant != 7 {
+		t.Fatalf("获取租户失败: %v %v", tenant, err)
+	}
+	_, err = Tenant[string](ctx, "users")
+	requireEntityErrCode(t, err, Err_0100030012.Code())
+}
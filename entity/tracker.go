@@ -2,6 +2,9 @@ package entity
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"slices"
 
 	"github.com/zodileap/taurus_go/entity/dialect"
 	stringutil "github.com/zodileap/taurus_go/stringutil"
@@ -52,6 +55,14 @@ type (
 		key    string
 		state  EntityState
 		fields []string
+		// snapshot 字段从数据库读取时的值。
+		snapshot map[string]FieldValue
+	}
+
+	// Change 字段的修改，Old为从数据库读取时的值，New为当前的值。
+	Change struct {
+		Old FieldValue
+		New FieldValue
 	}
 )
 
@@ -117,6 +128,82 @@ func (m Mutation) Fields() []string {
 	return m.fields
 }
 
+// SetSnapshot 记录字段从数据库读取时的值，并清空需要改变的字段。
+//
+// Params:
+//
+//   - values: 字段名和字段的值。
+func (m *Mutation) SetSnapshot(values map[string]FieldValue) {
+	m.snapshot = values
+	m.fields = nil
+}
+
+// Refresh 用重新从数据库读取的值刷新实体，返回需要把读取的值复制到实体的字段，并更新这些字段的快照。
+// 只处理读取了的字段，其他字段的值和快照保持不变；已经被修改的字段保留修改，只更新快照。
+//
+// Params:
+//
+//   - fields: 读取的字段，为空时表示读取了所有字段。
+//   - current: 实体当前的值。
+//   - values: 从数据库读取的值。
+//
+// Returns:
+//
+//	0: 需要复制到实体的字段，按照名称排序。
+func (m *Mutation) Refresh(fields []string, current, values map[string]FieldValue) []string {
+	if len(fields) == 0 {
+		fields = make([]string, 0, len(values))
+		for f := range values {
+			fields = append(fields, f)
+		}
+	}
+	if m.snapshot == nil {
+		m.snapshot = make(map[string]FieldValue, len(fields))
+	}
+	refreshed := []string{}
+	for _, f := range fields {
+		v, ok := values[f]
+		if !ok {
+			continue
+		}
+		if c, ok := current[f]; ok && !m.Changed(f, c) && !slices.Contains(refreshed, f) {
+			refreshed = append(refreshed, f)
+		}
+		m.snapshot[f] = v
+	}
+	slices.Sort(refreshed)
+	return refreshed
+}
+
+// Snapshot 获取字段从数据库读取时的值。
+//
+// Params:
+//
+//   - field: 字段名。
+//
+// Returns:
+//
+//	0: 字段的值。
+//	1: 是否存在快照。
+func (m Mutation) Snapshot(field string) (FieldValue, bool) {
+	v, ok := m.snapshot[field]
+	return v, ok
+}
+
+// Changed 判断字段的值和快照相比是否发生了变化，没有快照的字段都视为发生了变化。
+//
+// Params:
+//
+//   - field: 字段名。
+//   - v: 字段当前的值。
+func (m Mutation) Changed(field string, v FieldValue) bool {
+	old, ok := m.snapshot[field]
+	if !ok {
+		return true
+	}
+	return !reflect.DeepEqual(old, v)
+}
+
 // EntityState 实体类状态，用于标识实体类的状态。
 type EntityState = int16
 
@@ -134,3 +221,26 @@ const (
 	// Added 已添加，不存在于数据库中、属性已修改、调用Save()方法时，会执行插入操作。
 	Added EntityState = 4
 )
+
+// StateName 返回实体状态的名称，用于错误信息。
+//
+// Params:
+//
+//   - state: 实体的状态。
+func StateName(state EntityState) string {
+	switch state {
+	case NotSet:
+		return "NotSet"
+	case Detached:
+		return "Detached"
+	case Unchanged:
+		return "Unchanged"
+	case Deleted:
+		return "Deleted"
+	case Modified:
+		return "Modified"
+	case Added:
+		return "Added"
+	}
+	return fmt.Sprintf("EntityState(%d)", state)
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMutationSnapshot(t *testing.T) {
+	m := NewMutation(Unchanged)
+	m.SetFields("name")
+	m.SetSnapshot(map[string]FieldValue{"name": "a", "data": []byte("x")})
+
+	if len(m.Fields()) != 0 {
+		t.Fatalf("设置快照后应清空修改的字段: %v", m.Fields())
+	}
+	if v, ok := m.Snapshot("name"); !ok || v != "a" {
+		t.Fatalf("快照的值不正确: %v %v", v, ok)
+	}
+	if m.Changed("name", "a") || m.Changed("data", []byte("x")) {
+		t.Fatal("值相同时不应视为发生了变化")
+	}
+	if !m.Changed("name", "b") {
+		t.Fatal("值不同时应视为发生了变化")
+	}
+	if !m.Changed("age", nil) {
+		t.Fatal("没有快照的字段应视为发生了变化")
+	}
+}
+
+func TestMutationRefresh(t *testing.T) {
+	m := NewMutation(Modified)
+	m.SetSnapshot(map[string]FieldValue{"id": int64(1), "title": "a", "price": 10, "qty": 2})
+	m.SetFields("qty")
+	// 读取了整行之后，再只读取id和title，其他字段不应被刷新。
+	current := map[string]FieldValue{"id": int64(1), "title": "a", "price": 10, "qty": 3}
+	refreshed := m.Refresh([]string{"id", "title", "other"}, current, map[string]FieldValue{"id": int64(1), "title": "b", "price": 0, "qty": 0})
+	if !reflect.DeepEqual(refreshed, []string{"id", "title"}) {
+		t.Fatalf("只应刷新读取了的字段: %v", refreshed)
+	}
+	if v, _ := m.Snapshot("title"); v != "b" {
+		t.Fatalf("读取了的字段的快照不正确: %v", v)
+	}
+	if v, _ := m.Snapshot("price"); v != 10 {
+		t.Fatalf("没有读取的字段的快照不应改变: %v", v)
+	}
+	if len(m.Fields()) != 1 {
+		t.Fatalf("刷新时不应清空修改的字段: %v", m.Fields())
+	}
+	// 读取所有字段时，已经修改的qty保留修改，只更新快照。
+	refreshed = m.Refresh(nil, map[string]FieldValue{"id": int64(1), "title": "b", "price": 10, "qty": 3},
+		map[string]FieldValue{"id": int64(1), "title": "c", "price": 11, "qty": 4})
+	if !reflect.DeepEqual(refreshed, []string{"id", "price", "title"}) {
+		t.Fatalf("读取所有字段时应刷新没有修改的字段: %v", refreshed)
+	}
+	if v, _ := m.Snapshot("qty"); v != 4 {
+		t.Fatalf("修改了的字段的快照应更新: %v", v)
+	}
+}
+
+func TestStateName(t *testing.T) {
+	if StateName(Modified) != "Modified" || StateName(Detached) != "Detached" || StateName(9) != "EntityState(9)" {
+		t.Fatalf("实体状态的名称不正确: %s %s %s", StateName(Modified), StateName(Detached), StateName(9))
+	}
+}
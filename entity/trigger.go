@@ -0,0 +1,196 @@
+package entity
+
+// TriggerBuilder 触发器构建器，在数据库的Triggers()方法中返回，
+// codegen会检查时机、事件和级别的组合，并转换为[TriggerConfig]。
+type TriggerBuilder interface {
+	// Descriptor codegen中使用，用于获取触发器的描述。
+	Descriptor() *TriggerDescriptor
+}
+
+// TriggerTiming 触发器的触发时机。
+type TriggerTiming string
+
+const (
+	// TriggerBefore 在操作之前触发。
+	TriggerBefore TriggerTiming = "BEFORE"
+	// TriggerAfter 在操作之后触发。
+	TriggerAfter TriggerTiming = "AFTER"
+	// TriggerInsteadOf 代替视图上的操作，只能用于视图的行级触发器。
+	TriggerInsteadOf TriggerTiming = "INSTEAD OF"
+)
+
+// TriggerEvent 触发器的触发事件。
+type TriggerEvent string
+
+const (
+	// TriggerInsert 新增。
+	TriggerInsert TriggerEvent = "INSERT"
+	// TriggerUpdate 更新。
+	TriggerUpdate TriggerEvent = "UPDATE"
+	// TriggerDelete 删除。
+	TriggerDelete TriggerEvent = "DELETE"
+	// TriggerTruncate 清空表，只能用于语句级触发器。
+	TriggerTruncate TriggerEvent = "TRUNCATE"
+)
+
+// TriggerLevel 触发器的级别。
+type TriggerLevel string
+
+const (
+	// TriggerRow 每一行触发一次，FOR EACH ROW。
+	TriggerRow TriggerLevel = "ROW"
+	// TriggerStatement 每个语句触发一次，FOR EACH STATEMENT。
+	TriggerStatement TriggerLevel = "STATEMENT"
+)
+
+// TriggerDescriptor 触发器的描述。
+type TriggerDescriptor struct {
+	// Name 触发器的名称，触发器函数的名称为Name加上"_trigger_func"。
+	Name string
+	// Entity 触发器作用的实体表或者视图，触发器和函数在实体所在的模式中。
+	Entity EntityInterface
+	// Timing 触发时机。
+	Timing TriggerTiming
+	// Events 触发事件，按照添加的顺序排列。
+	Events []TriggerEvent
+	// UpdateOf UPDATE OF中的字段，为空时更新任何字段都会触发。
+	UpdateOf []FieldBuilder
+	// Level 触发器的级别。
+	Level TriggerLevel
+	// When 触发条件，WHEN子句中的表达式，例如"NEW.age > 0"。
+	When string
+	// Declare 触发器函数中DECLARE声明的变量，例如"total integer;"。
+	Declare string
+	// Body 触发器函数BEGIN和END之间的PL/pgSQL语句，需要包含RETURN。
+	Body string
+	// Arguments 传给触发器函数的参数，在函数中通过TG_ARGV读取。
+	Arguments []string
+}
+
+// Trigger 触发器。
+type Trigger struct {
+	desc *TriggerDescriptor
+}
+
+// InitTrigger 初始化一个行级触发器。
+//
+// Params:
+//
+//   - name: 触发器的名称。
+func InitTrigger(name string) *Trigger {
+	return &Trigger{desc: &TriggerDescriptor{Name: name, Level: TriggerRow}}
+}
+
+// Descriptor codegen中使用，用于获取触发器的描述。
+func (t *Trigger) Descriptor() *TriggerDescriptor {
+	return t.desc
+}
+
+// On 设置触发器作用的实体表或者视图。
+//
+// Params:
+//
+//   - e: 数据库中的实体，例如&d.User。
+func (t *Trigger) On(e EntityInterface) *Trigger {
+	t.desc.Entity = e
+	return t
+}
+
+// Before 在操作之前触发。
+func (t *Trigger) Before() *Trigger {
+	t.desc.Timing = TriggerBefore
+	return t
+}
+
+// After 在操作之后触发。
+func (t *Trigger) After() *Trigger {
+	t.desc.Timing = TriggerAfter
+	return t
+}
+
+// InsteadOf 代替视图上的操作。
+func (t *Trigger) InsteadOf() *Trigger {
+	t.desc.Timing = TriggerInsteadOf
+	return t
+}
+
+// Insert 新增时触发。
+func (t *Trigger) Insert() *Trigger {
+	t.desc.Events = append(t.desc.Events, TriggerInsert)
+	return t
+}
+
+// Update 更新时触发，设置了字段时只有更新这些字段才会触发。
+//
+// Params:
+//
+//   - fs: UPDATE OF中的字段。
+func (t *Trigger) Update(fs ...FieldBuilder) *Trigger {
+	t.desc.Events = append(t.desc.Events, TriggerUpdate)
+	t.desc.UpdateOf = append(t.desc.UpdateOf, fs...)
+	return t
+}
+
+// Delete 删除时触发。
+func (t *Trigger) Delete() *Trigger {
+	t.desc.Events = append(t.desc.Events, TriggerDelete)
+	return t
+}
+
+// Truncate 清空表时触发。
+func (t *Trigger) Truncate() *Trigger {
+	t.desc.Events = append(t.desc.Events, TriggerTruncate)
+	return t
+}
+
+// ForEachRow 每一行触发一次。
+func (t *Trigger) ForEachRow() *Trigger {
+	t.desc.Level = TriggerRow
+	return t
+}
+
+// ForEachStatement 每个语句触发一次。
+func (t *Trigger) ForEachStatement() *Trigger {
+	t.desc.Level = TriggerStatement
+	return t
+}
+
+// When 设置触发条件。
+//
+// Params:
+//
+//   - condition: WHEN子句中的表达式，例如"OLD.name IS DISTINCT FROM NEW.name"。
+func (t *Trigger) When(condition string) *Trigger {
+	t.desc.When = condition
+	return t
+}
+
+// Declare 设置触发器函数中声明的变量。
+//
+// Params:
+//
+//   - declarations: DECLARE中的声明，例如"total integer;"。
+func (t *Trigger) Declare(declarations string) *Trigger {
+	t.desc.Declare = declarations
+	return t
+}
+
+// Body 设置触发器函数的PL/pgSQL语句，codegen会生成CREATE OR REPLACE FUNCTION。
+//
+// Params:
+//
+//   - plpgsql: BEGIN和END之间的语句，例如"NEW.updated_at := now(); RETURN NEW;"。
+func (t *Trigger) Body(plpgsql string) *Trigger {
+	t.desc.Body = plpgsql
+	return t
+}
+
+// Arguments 设置传给触发器函数的参数。
+//
+// Params:
+//
+//   - args: 参数，在函数中通过TG_ARGV[0]、TG_ARGV[1]读取。
+func (t *Trigger) Arguments(args ...string) *Trigger {
+	t.desc.Arguments = append(t.desc.Arguments, args...)
+	return t
+}
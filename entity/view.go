@@ -0,0 +1,49 @@
+package entity
+
+import "fmt"
+
+type (
+	// View 视图，和[Entity]一样嵌入到结构体中，用于定义数据库中的视图或者物化视图。
+	// 视图是只读的，生成的代码中只有查询，没有新增、更新和删除。
+	//
+	// 视图需要在Fields()中设置一个主键字段，用于区分查询结果中的每一行，
+	// 主键字段只在生成代码中使用，不会在数据库中创建约束。
+	View struct {
+		Entity
+	}
+
+	// ViewInterface 视图的接口。
+	ViewInterface interface {
+		EntityInterface
+		// ViewConfig 视图的配置。
+		ViewConfig() ViewConfig
+	}
+
+	// ViewConfig 视图的配置。
+	ViewConfig struct {
+		// Query 视图的SELECT语句。
+		Query string
+		// Builder 生成视图的SELECT语句，Query为空时使用，例如entitysql中的查询生成器。
+		// 生成的语句不能带有参数。
+		Builder fmt.Stringer
+		// Materialized 是否是物化视图，物化视图会生成Refresh方法。
+		Materialized bool
+	}
+)
+
+// ViewConfig 视图的配置，需要在视图中覆盖这个方法。
+func (View) ViewConfig() ViewConfig {
+	return ViewConfig{}
+}
+
+// Definition 返回视图的SELECT语句。
+//
+// Returns:
+//
+//	0: 视图的SELECT语句。
+func (c ViewConfig) Definition() string {
+	if c.Query == "" && c.Builder != nil {
+		return c.Builder.String()
+	}
+	return c.Query
+}
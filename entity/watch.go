@@ -0,0 +1,16 @@
+package entity
+
+// ChangeOp 监听实体表时收到的变化的类型。
+type ChangeOp string
+
+const (
+	// ChangeInsert 新增。
+	ChangeInsert ChangeOp = "INSERT"
+	// ChangeUpdate 更新。
+	ChangeUpdate ChangeOp = "UPDATE"
+	// ChangeDelete 删除。
+	ChangeDelete ChangeOp = "DELETE"
+	// ChangeReset 监听的连接断开后重新建立，断开期间的变化可能已经丢失，
+	// 依赖变化失效的缓存需要全部失效。
+	ChangeReset ChangeOp = "RESET"
+)
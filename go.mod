@@ -12,6 +12,7 @@ require (
 	github.com/spf13/cobra v1.9.1
 	golang.org/x/text v0.23.0
 	golang.org/x/tools v0.31.0
+	google.golang.org/grpc v1.73.0
 )
 
 require (
@@ -25,6 +26,5 @@ require (
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
-	google.golang.org/grpc v1.73.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 )